@@ -1,14 +1,27 @@
 package context
 
 import (
+	"bytes"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
+	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 )
 
+// ErrBodyTooLarge is returned when the request body exceeds the size limit
+// set on the router or route. The router answers it with 413 Request Entity
+// Too Large unless an error handler takes care of it.
+var ErrBodyTooLarge = errors.New("request body too large")
+
 type Body struct {
 	ctx *Context
+
+	buffered bool   // keep the body so it can be read more than once
+	raw      []byte // body read in buffered mode
+	read     bool   // raw holds the whole body
 }
 
 // NewBody creates a new instance of Body.
@@ -16,38 +29,97 @@ func NewBody(ctx *Context) *Body {
 	return &Body{ctx: ctx}
 }
 
+// reset drops the state kept for the previous request.
+func (b *Body) reset() {
+	b.buffered = false
+	b.raw = nil
+	b.read = false
+}
+
+// EnableBuffering keeps the body in memory once it is read, so it can be read
+// again, for example by a middleware checking a signature and then by the
+// handler. Request.Body is replaced with a fresh reader after every read.
+// Without it the body is streamed and can only be read once.
+func (b *Body) EnableBuffering() {
+	b.buffered = true
+}
+
 // ReadRaw reads the raw request body and returns it as bytes.
 func (b *Body) ReadRaw() ([]byte, error) {
+	if b.read {
+		b.rewind()
+		return b.raw, nil
+	}
+
 	bodyBytes, err := io.ReadAll(b.ctx.Request.Body)
 	if err != nil {
-		return nil, err
+		return nil, bodyError(err)
+	}
+	if b.buffered {
+		b.raw, b.read = bodyBytes, true
+		b.rewind()
 	}
 	return bodyBytes, nil
 }
 
-// ParseJSON parses the request body as JSON into the provided struct.
+// ParseJSON parses the request body as JSON into the provided struct. The
+// body is decoded as it is read rather than buffered first.
 func (b *Body) ParseJSON(v interface{}) error {
-	bodyBytes, err := b.ReadRaw()
+	r, err := b.reader()
 	if err != nil {
 		return err
 	}
-	return json.Unmarshal(bodyBytes, v)
+	return bodyError(json.NewDecoder(r).Decode(v))
 }
 
-// ParseXML parses the request body as XML into the provided struct.
+// ParseXML parses the request body as XML into the provided struct. The body
+// is decoded as it is read rather than buffered first.
 func (b *Body) ParseXML(v interface{}) error {
-	bodyBytes, err := b.ReadRaw()
+	r, err := b.reader()
 	if err != nil {
 		return err
 	}
-	return xml.Unmarshal(bodyBytes, v)
+	return bodyError(xml.NewDecoder(r).Decode(v))
 }
 
 // ParseForm parses the request body as form data.
 func (b *Body) ParseForm() (values url.Values, err error) {
+	if b.buffered {
+		if _, err := b.ReadRaw(); err != nil {
+			return nil, err
+		}
+	}
 	err = b.ctx.Request.ParseForm()
 	if err != nil {
-		return nil, err
+		return nil, bodyError(err)
 	}
 	return b.ctx.Request.PostForm, nil
 }
+
+// reader returns the reader a decoder consumes the body from: the request
+// body itself, or the buffered copy in buffered mode.
+func (b *Body) reader() (io.Reader, error) {
+	if !b.buffered {
+		return b.ctx.Request.Body, nil
+	}
+
+	raw, err := b.ReadRaw()
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(raw), nil
+}
+
+// rewind replaces the request body with a reader over the buffered bytes.
+func (b *Body) rewind() {
+	b.ctx.Request.Body = io.NopCloser(bytes.NewReader(b.raw))
+}
+
+// bodyError reports a body cut off by its size limit as ErrBodyTooLarge.
+func bodyError(err error) error {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return fmt.Errorf("%w: limit is %d bytes", ErrBodyTooLarge, maxErr.Limit)
+	}
+	return err
+}
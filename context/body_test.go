@@ -0,0 +1,112 @@
+package context
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newBodyContext(body string, limit int64) *Context {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	if limit > 0 {
+		req.Body = http.MaxBytesReader(w, req.Body, limit)
+	}
+	return NewContext(w, req)
+}
+
+func TestBodyBuffering(t *testing.T) {
+	c := newBodyContext(`{"name":"nex"}`, 0)
+	c.Body.EnableBuffering()
+
+	raw, err := c.Body.ReadRaw()
+	if err != nil || string(raw) != `{"name":"nex"}` {
+		t.Fatalf("ReadRaw() = %q, %v", raw, err)
+	}
+	if again, err := c.Body.ReadRaw(); err != nil || string(again) != string(raw) {
+		t.Errorf("second ReadRaw() = %q, %v, want %q", again, err, raw)
+	}
+
+	var v struct{ Name string }
+	if err := c.Body.ParseJSON(&v); err != nil || v.Name != "nex" {
+		t.Errorf("ParseJSON after ReadRaw = %+v, %v", v, err)
+	}
+	if rest, err := io.ReadAll(c.Request.Body); err != nil || string(rest) != string(raw) {
+		t.Errorf("Request.Body after buffered reads = %q, %v, want %q", rest, err, raw)
+	}
+}
+
+func TestBodyStreaming(t *testing.T) {
+	c := newBodyContext(`{"name":"nex"}`, 0)
+
+	var v struct{ Name string }
+	if err := c.Body.ParseJSON(&v); err != nil || v.Name != "nex" {
+		t.Fatalf("ParseJSON() = %+v, %v", v, err)
+	}
+	if raw, err := c.Body.ReadRaw(); err != nil || len(raw) != 0 {
+		t.Errorf("ReadRaw() after streaming = %q, %v, want nothing left", raw, err)
+	}
+}
+
+func TestBodyBufferedForm(t *testing.T) {
+	c := newBodyContext("a=1&b=2", 0)
+	c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	c.Body.EnableBuffering()
+
+	values, err := c.Body.ParseForm()
+	if err != nil || values.Get("a") != "1" || values.Get("b") != "2" {
+		t.Fatalf("ParseForm() = %v, %v", values, err)
+	}
+	if raw, err := c.Body.ReadRaw(); err != nil || string(raw) != "a=1&b=2" {
+		t.Errorf("ReadRaw() after ParseForm = %q, %v", raw, err)
+	}
+}
+
+func TestBodyTooLarge(t *testing.T) {
+	jsonBody := `{"name":"` + strings.Repeat("x", 64) + `"}`
+	xmlBody := "<v><Name>" + strings.Repeat("x", 64) + "</Name></v>"
+
+	tests := []struct {
+		name     string
+		body     string
+		buffered bool
+		read     func(b *Body) error
+	}{
+		{name: "ReadRaw", body: jsonBody, read: func(b *Body) error { _, err := b.ReadRaw(); return err }},
+		{name: "ParseJSON", body: jsonBody, read: func(b *Body) error { var v map[string]string; return b.ParseJSON(&v) }},
+		{name: "buffered ParseJSON", body: jsonBody, buffered: true, read: func(b *Body) error { var v map[string]string; return b.ParseJSON(&v) }},
+		{name: "ParseXML", body: xmlBody, read: func(b *Body) error { var v struct{ Name string }; return b.ParseXML(&v) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newBodyContext(tt.body, 16)
+			if tt.buffered {
+				c.Body.EnableBuffering()
+			}
+			if err := tt.read(c.Body); !errors.Is(err, ErrBodyTooLarge) {
+				t.Errorf("error = %v, want %v", err, ErrBodyTooLarge)
+			}
+		})
+	}
+}
+
+func TestBodyReset(t *testing.T) {
+	c := AcquireContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader("first")))
+	c.Body.EnableBuffering()
+	if _, err := c.Body.ReadRaw(); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Reset(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader("second")))
+	if raw, err := c.Body.ReadRaw(); err != nil || string(raw) != "second" {
+		t.Errorf("ReadRaw() after Reset = %q, %v, want %q", raw, err, "second")
+	}
+	if c.Body.buffered {
+		t.Error("buffering survived Reset")
+	}
+	ReleaseContext(c)
+}
@@ -12,7 +12,7 @@ import (
 type Context struct {
 	Request    *http.Request
 	Response   http.ResponseWriter
-	Params     map[string]string // for route parameters
+	Params     Params // for route parameters
 	Res        *NexResponse
 	PathParam  *PathParam
 	QueryParam *QueryParam
@@ -23,22 +23,68 @@ type Context struct {
 	err        error
 }
 
-// NewContext creates a new instance of Context.
-func NewContext(w http.ResponseWriter, r *http.Request) *Context {
-	ctx := Context{
-		Request:  r,
-		Response: w,
-		Params:   make(map[string]string),
+// paramsCap is the number of path params a pooled context holds without
+// growing its Params.
+const paramsCap = 8
+
+// contextPool recycles contexts between requests.
+var contextPool = sync.Pool{
+	New: func() any {
+		return newContext()
+	},
+}
+
+// newContext allocates a context together with its helpers.
+func newContext() *Context {
+	ctx := &Context{
+		Params: make(Params, 0, paramsCap),
 	}
 
 	// Response is wrapped in a NexResponse
-	ctx.Res = NewNexResponse(&ctx)
-	ctx.PathParam = NewPathParam(&ctx)
-	ctx.QueryParam = NewQueryParam(&ctx)
-	ctx.Form = NewForm(&ctx)
-	ctx.Body = NewBody(&ctx)
+	ctx.Res = NewNexResponse(ctx)
+	ctx.PathParam = NewPathParam(ctx)
+	ctx.QueryParam = NewQueryParam(ctx)
+	ctx.Form = NewForm(ctx)
+	ctx.Body = NewBody(ctx)
 
-	return &ctx
+	return ctx
+}
+
+// NewContext creates a new instance of Context.
+func NewContext(w http.ResponseWriter, r *http.Request) *Context {
+	ctx := newContext()
+	ctx.Reset(w, r)
+	return ctx
+}
+
+// AcquireContext returns a context from the pool, reset to serve the request.
+// Pass it to ReleaseContext once the request has been served.
+func AcquireContext(w http.ResponseWriter, r *http.Request) *Context {
+	ctx := contextPool.Get().(*Context)
+	ctx.Reset(w, r)
+	return ctx
+}
+
+// ReleaseContext returns the context to the pool. Neither the context nor its
+// helpers may be used afterwards, so handlers must not keep them beyond the
+// request, for example in a goroutine.
+func ReleaseContext(ctx *Context) {
+	ctx.Reset(nil, nil)
+	contextPool.Put(ctx)
+}
+
+// Reset prepares the context to serve another request, dropping the params,
+// data and error of the previous one.
+func (c *Context) Reset(w http.ResponseWriter, r *http.Request) {
+	c.Request = r
+	c.Response = w
+	for i := range c.Params {
+		c.Params[i] = Param{}
+	}
+	c.Params = c.Params[:0]
+	c.Data = nil
+	c.err = nil
+	c.Body.reset()
 }
 
 // Error returns the error set in the context.
@@ -51,6 +97,14 @@ func (c *Context) SetError(err error) {
 	c.err = err
 }
 
+// SetParamValue stores the typed value of a path param, as converted by the
+// param's type constraint, so it does not need to be parsed again.
+func (c *Context) SetParamValue(name string, value any) {
+	if i := c.Params.index(name); i != -1 {
+		c.Params[i].typed = value
+	}
+}
+
 // String writes a string response to the client.
 func (c *Context) String(status int, s string) {
 	c.Response.WriteHeader(status)
@@ -0,0 +1,49 @@
+package context
+
+// Param is a path param captured while matching a route.
+type Param struct {
+	Key   string
+	Value string
+	typed any // value converted by the param's type, if it has one
+}
+
+// Params holds the path params of a request in the order they appear in the
+// route, after the host placeholders. A later param shadows an earlier one
+// with the same key.
+type Params []Param
+
+// Get returns the value of the param with the given key, or an empty string
+// if it is absent.
+func (ps Params) Get(key string) string {
+	value, _ := ps.Lookup(key)
+	return value
+}
+
+// Lookup returns the value of the param with the given key and whether it is
+// present.
+func (ps Params) Lookup(key string) (string, bool) {
+	if i := ps.index(key); i != -1 {
+		return ps[i].Value, true
+	}
+	return "", false
+}
+
+// Set sets the value of the param with the given key, adding the param if it
+// is absent.
+func (ps *Params) Set(key, value string) {
+	if i := ps.index(key); i != -1 {
+		(*ps)[i] = Param{Key: key, Value: value}
+		return
+	}
+	*ps = append(*ps, Param{Key: key, Value: value})
+}
+
+// index returns the position of the last param with the given key, or -1.
+func (ps Params) index(key string) int {
+	for i := len(ps) - 1; i >= 0; i-- {
+		if ps[i].Key == key {
+			return i
+		}
+	}
+	return -1
+}
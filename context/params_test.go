@@ -0,0 +1,33 @@
+package context
+
+import "testing"
+
+func TestParams(t *testing.T) {
+	var ps Params
+	ps.Set("id", "1")
+	ps.Set("page", "")
+	ps = append(ps, Param{Key: "id", Value: "2"})
+
+	tests := []struct {
+		key   string
+		value string
+		ok    bool
+	}{
+		{key: "id", value: "2", ok: true},
+		{key: "page", value: "", ok: true},
+		{key: "missing", value: "", ok: false},
+	}
+	for _, tt := range tests {
+		if value, ok := ps.Lookup(tt.key); value != tt.value || ok != tt.ok {
+			t.Errorf("Lookup(%q) = %q, %v, want %q, %v", tt.key, value, ok, tt.value, tt.ok)
+		}
+		if value := ps.Get(tt.key); value != tt.value {
+			t.Errorf("Get(%q) = %q, want %q", tt.key, value, tt.value)
+		}
+	}
+
+	ps.Set("id", "3")
+	if len(ps) != 3 || ps.Get("id") != "3" || ps[0].Value != "1" {
+		t.Errorf("Set(id) on shadowed params = %+v, want the last id replaced", ps)
+	}
+}
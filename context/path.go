@@ -17,19 +17,40 @@ func NewPathParam(ctx *Context) *PathParam {
 
 // fetchValue fetches a value from context and checks if it exists.
 func (p *PathParam) fetchValue(name string) (string, error) {
-	strValue := p.ctx.Params[name]
+	strValue := p.ctx.Params.Get(name)
 	if strValue == "" {
 		return "", fmt.Errorf("path parameter %s not found", name)
 	}
 	return strValue, nil
 }
 
+// Get returns the value of the path parameter with the given name, or an
+// empty string if it is absent.
 func (p *PathParam) Get(name string) string {
-	return p.ctx.Params[name]
+	return p.ctx.Params.Get(name)
+}
+
+// Lookup returns the value of the path parameter with the given name and
+// whether it is present. An optional parameter missing from the request path
+// is absent.
+func (p *PathParam) Lookup(name string) (string, bool) {
+	return p.ctx.Params.Lookup(name)
+}
+
+// Value returns the typed value of a param declared with a type constraint
+// such as ":id<int>", or nil if the param has no type.
+func (p *PathParam) Value(name string) any {
+	if i := p.ctx.Params.index(name); i != -1 {
+		return p.ctx.Params[i].typed
+	}
+	return nil
 }
 
 // Integer related methods
 func (p *PathParam) GetAsInt(name string) (int, error) {
+	if value, ok := p.Value(name).(int); ok {
+		return value, nil
+	}
 	strValue, err := p.fetchValue(name)
 	if err != nil {
 		return 0, err
@@ -38,6 +59,9 @@ func (p *PathParam) GetAsInt(name string) (int, error) {
 }
 
 func (p *PathParam) GetAsInt64(name string) (int64, error) {
+	if value, ok := p.Value(name).(int); ok {
+		return int64(value), nil
+	}
 	strValue, err := p.fetchValue(name)
 	if err != nil {
 		return 0, err
@@ -56,6 +80,9 @@ func (p *PathParam) GetAsBool(name string) (bool, error) {
 
 // UUID method
 func (p *PathParam) GetAsUUID(name string) (uuid.UUID, error) {
+	if value, ok := p.Value(name).(uuid.UUID); ok {
+		return value, nil
+	}
 	strValue, err := p.fetchValue(name)
 	if err != nil {
 		return uuid.UUID{}, err
@@ -10,6 +10,7 @@ require (
 	github.com/nex-gen-tech/nexlog v1.0.3
 	github.com/rjeczalik/notify v0.9.3
 	github.com/spf13/cast v1.5.0
+	golang.org/x/net v0.10.0
 )
 
 require (
@@ -18,6 +19,7 @@ require (
 	github.com/mattn/go-isatty v0.0.17 // indirect
 	github.com/stretchr/testify v1.8.2 // indirect
 	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e // indirect
+	golang.org/x/text v0.9.0 // indirect
 )
 
 require (
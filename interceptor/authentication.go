@@ -48,7 +48,7 @@ func Authentication() router.MiddlewareFunc {
 
 			if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
 				// Add claims to context or do other authentication logic
-				c.Params["userID"] = string(claims["userID"].(string))
+				c.Params.Set("userID", claims["userID"].(string))
 				next(c)
 			} else {
 				unauthorized(c, "Invalid token")
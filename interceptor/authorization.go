@@ -20,7 +20,7 @@ func Authorization(requiredPermission string) router.MiddlewareFunc {
 		return func(c *context.Context) {
 			// Fetch the user's role. This could be part of the JWT or fetched from a database.
 			// For this example, we'll assume it's part of the JWT claims.
-			userRole := c.Params["role"]
+			userRole := c.Params.Get("role")
 
 			// Check if the user's role has the required permission
 			if hasPermission(userRole, requiredPermission) {
@@ -29,7 +29,7 @@ func Logging() router.MiddlewareFunc {
 			// Extract details from the request
 			timestamp := start.Format(time.RFC3339) // use the start time as the timestamp
 			method := c.Request.Method
-			reqID := c.Params["requestID"] // assuming the requestID is saved as a route parameter
+			reqID := c.Params.Get("requestID") // assuming the requestID is saved as a route parameter
 			path := c.Request.URL.Path
 			ip := c.Request.RemoteAddr
 
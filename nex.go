@@ -6,10 +6,30 @@ import (
 )
 
 type (
-	Context     = context.Context
-	Router      = router.Router
-	HandlerFunc = router.HandlerFunc
-	RouterGroup = router.RouterGroup
+	Context        = context.Context
+	Router         = router.Router
+	HandlerFunc    = router.HandlerFunc
+	MiddlewareFunc = router.MiddlewareFunc
+	RouterGroup    = router.RouterGroup
+	Route          = router.Route
+	RouteInfo      = router.RouteInfo
+	ServerConfig   = router.ServerConfig
+	ShutdownError  = router.ShutdownError
+	HTTP2Config    = router.HTTP2Config
+	Transport      = router.Transport
+)
+
+// HTTP methods accepted by Router.AddRoute and Router.Match.
+const (
+	MethodGet     = router.MethodGet
+	MethodPost    = router.MethodPost
+	MethodPut     = router.MethodPut
+	MethodDelete  = router.MethodDelete
+	MethodPatch   = router.MethodPatch
+	MethodHead    = router.MethodHead
+	MethodOptions = router.MethodOptions
+	MethodConnect = router.MethodConnect
+	MethodTrace   = router.MethodTrace
 )
 
 // New - Create a new router
@@ -0,0 +1,103 @@
+package router
+
+import (
+	"net/http"
+	"testing"
+
+	nexctx "github.com/nex-gen-tech/nex/context"
+)
+
+// discardWriter is a http.ResponseWriter that drops everything written to it
+// without allocating.
+type discardWriter struct {
+	header http.Header
+}
+
+func (w *discardWriter) Header() http.Header         { return w.header }
+func (w *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardWriter) WriteHeader(int)             {}
+
+func benchmarkRequest(b *testing.B, r *Router, method, path string) {
+	req, err := http.NewRequest(method, path, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	w := &discardWriter{header: make(http.Header)}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.ServeHTTP(w, req)
+	}
+}
+
+func BenchmarkStaticRoute(b *testing.B) {
+	r := NewRouter()
+	r.GET("/", func(c *nexctx.Context) {})
+	r.GET("/users", func(c *nexctx.Context) {})
+	r.GET("/users/me/settings", func(c *nexctx.Context) {})
+
+	benchmarkRequest(b, r, MethodGet, "/users/me/settings")
+}
+
+func BenchmarkParamRoute(b *testing.B) {
+	r := NewRouter()
+	r.GET("/users/:id", func(c *nexctx.Context) {
+		_ = c.PathParam.Get("id")
+	})
+
+	benchmarkRequest(b, r, MethodGet, "/users/42")
+}
+
+func BenchmarkMultipleParamsRoute(b *testing.B) {
+	r := NewRouter()
+	r.GET("/orgs/:org/repos/:repo/issues/:number", func(c *nexctx.Context) {
+		_ = c.PathParam.Get("org")
+		_ = c.PathParam.Get("repo")
+		_ = c.PathParam.Get("number")
+	})
+
+	benchmarkRequest(b, r, MethodGet, "/orgs/nex/repos/router/issues/19")
+}
+
+func BenchmarkMiddlewareRoute(b *testing.B) {
+	r := NewRouter()
+	r.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *nexctx.Context) { next(c) }
+	})
+	r.GET("/users/:id", func(c *nexctx.Context) {})
+
+	benchmarkRequest(b, r, MethodGet, "/users/42")
+}
+
+func TestZeroAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+
+	r := NewRouter()
+	r.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *nexctx.Context) { next(c) }
+	})
+	r.GET("/users/me/settings", func(c *nexctx.Context) {})
+	r.GET("/users/:id", func(c *nexctx.Context) {
+		_ = c.PathParam.Get("id")
+	})
+	r.GET("/orgs/:org/repos/:repo/issues/:number", func(c *nexctx.Context) {
+		_ = c.PathParam.Get("org")
+		_ = c.PathParam.Get("repo")
+		_ = c.PathParam.Get("number")
+	})
+
+	for _, path := range []string{"/users/me/settings", "/users/42", "/orgs/nex/repos/router/issues/19"} {
+		req, err := http.NewRequest(MethodGet, path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := &discardWriter{header: make(http.Header)}
+
+		if allocs := testing.AllocsPerRun(100, func() { r.ServeHTTP(w, req) }); allocs != 0 {
+			t.Errorf("GET %s: %v allocs per request, want 0", path, allocs)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package router
+
+// fallbackChains holds the handlers for unmatched requests wrapped in the
+// global middlewares.
+type fallbackChains struct {
+	notFound             HandlerFunc
+	methodNotAllowed     HandlerFunc
+	options              HandlerFunc
+	notAcceptable        HandlerFunc
+	unsupportedMediaType HandlerFunc
+	payloadTooLarge      HandlerFunc
+	redirect             HandlerFunc
+}
+
+// compose wraps handler in the middlewares of each stack in turn, so that the
+// first middleware of the first stack runs outermost.
+func compose(handler HandlerFunc, stacks ...[]MiddlewareFunc) HandlerFunc {
+	for i := len(stacks) - 1; i >= 0; i-- {
+		for j := len(stacks[i]) - 1; j >= 0; j-- {
+			handler = stacks[i][j](handler)
+		}
+	}
+	return handler
+}
+
+// compile builds the middleware chain of every route and fallback handler
+// so requests are served without composing middlewares. It runs whenever the
+// global middlewares or a fallback handler change.
+func (r *Router) compile() {
+	for _, rt := range r.routes {
+		rt.chain = compose(rt.handler, r.middlewares, rt.middlewares)
+	}
+
+	r.fallbacks = fallbackChains{
+		notFound:             compose(r.notFound, r.middlewares),
+		methodNotAllowed:     compose(r.methodNotAllowed, r.middlewares),
+		options:              compose(optionsHandler, r.middlewares),
+		notAcceptable:        compose(r.notAcceptable, r.middlewares),
+		unsupportedMediaType: compose(r.unsupportedMediaType, r.middlewares),
+		payloadTooLarge:      compose(payloadTooLargeHandler, r.middlewares),
+		redirect:             compose(redirectHandler, r.middlewares),
+	}
+	for _, s := range r.scopes {
+		if s.notFound != nil {
+			s.notFoundChain = compose(s.notFound, r.middlewares)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors reported when a route cannot be registered.
+var (
+	ErrInvalidPattern = errors.New("invalid route pattern")
+	ErrDuplicateRoute = errors.New("duplicate route")
+	ErrParamConflict  = errors.New("conflicting param names")
+	ErrAmbiguousRoute = errors.New("ambiguous route")
+	ErrDuplicateName  = errors.New("duplicate route name")
+)
+
+// RouteError describes a route that could not be registered.
+type RouteError struct {
+	Methods []string
+	Path    string
+	Err     error
+}
+
+// Error implements the error interface.
+func (e *RouteError) Error() string {
+	if len(e.Methods) == 0 {
+		return fmt.Sprintf("route %s: %v", e.Path, e.Err)
+	}
+	return fmt.Sprintf("route %s %s: %v", strings.Join(e.Methods, ","), e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error so errors.Is can match the Err* values.
+func (e *RouteError) Unwrap() error {
+	return e.Err
+}
+
+// RouteErrors is the list of errors collected while registering routes.
+type RouteErrors []*RouteError
+
+// Error implements the error interface.
+func (e RouteErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d invalid route(s):\n\t%s", len(e), strings.Join(messages, "\n\t"))
+}
@@ -6,6 +6,8 @@ type MiddlewareFunc func(HandlerFunc) HandlerFunc
 type RouterGroup struct {
 	prefix      string
 	router      *Router
+	tree        *Tree  // routes of a host group; the router's tree if nil
+	host        string // host pattern of a host group
 	middlewares []MiddlewareFunc
 }
 
@@ -14,7 +16,9 @@ func (group *RouterGroup) Group(prefix string) *RouterGroup {
 	return &RouterGroup{
 		prefix:      group.prefix + prefix,
 		router:      group.router,
-		middlewares: group.middlewares, // inherit the parent's middlewares
+		tree:        group.tree,
+		host:        group.host,
+		middlewares: group.middlewares[:len(group.middlewares):len(group.middlewares)], // inherit the parent's middlewares
 	}
 }
 
@@ -24,33 +28,68 @@ func (group *RouterGroup) Use(middleware ...MiddlewareFunc) {
 	group.middlewares = append(group.middlewares, middleware...)
 }
 
-// addRoute is an internal method to handle adding routes with the provided method, path, handler, and middlewares.
-func (group *RouterGroup) addRoute(method, path string, handler HandlerFunc, middlewares ...MiddlewareFunc) {
-	allMiddlewares := append(group.middlewares, middlewares...)
-	group.router.AddRoute(method, group.prefix+path, handler, allMiddlewares...)
+// addRoute is an internal method to handle adding routes with the provided methods, path, handler, and middlewares.
+func (group *RouterGroup) addRoute(methods []string, path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	tree := group.tree
+	if tree == nil {
+		tree = group.router.tree
+	}
+
+	allMiddlewares := append(group.middlewares[:len(group.middlewares):len(group.middlewares)], middlewares...)
+	return group.router.addRoute(tree, group.host, group.prefix, methods, group.prefix+path, handler, allMiddlewares)
 }
 
 // GET adds a new route with the GET method to the group.
-func (group *RouterGroup) GET(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) {
-	group.addRoute(MethodGet, path, handler, middlewares...)
+func (group *RouterGroup) GET(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return group.addRoute([]string{MethodGet}, path, handler, middlewares...)
 }
 
 // POST adds a new route with the POST method to the group.
-func (group *RouterGroup) POST(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) {
-	group.addRoute(MethodPost, path, handler, middlewares...)
+func (group *RouterGroup) POST(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return group.addRoute([]string{MethodPost}, path, handler, middlewares...)
 }
 
 // PUT adds a new route with the PUT method to the group.
-func (group *RouterGroup) PUT(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) {
-	group.addRoute(MethodPut, path, handler, middlewares...)
+func (group *RouterGroup) PUT(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return group.addRoute([]string{MethodPut}, path, handler, middlewares...)
 }
 
 // DELETE adds a new route with the DELETE method to the group.
-func (group *RouterGroup) DELETE(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) {
-	group.addRoute(MethodDelete, path, handler, middlewares...)
+func (group *RouterGroup) DELETE(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return group.addRoute([]string{MethodDelete}, path, handler, middlewares...)
 }
 
 // PATCH adds a new route with the PATCH method to the group.
-func (group *RouterGroup) PATCH(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) {
-	group.addRoute(MethodPatch, path, handler, middlewares...)
+func (group *RouterGroup) PATCH(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return group.addRoute([]string{MethodPatch}, path, handler, middlewares...)
+}
+
+// HEAD adds a new route with the HEAD method to the group.
+func (group *RouterGroup) HEAD(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return group.addRoute([]string{MethodHead}, path, handler, middlewares...)
+}
+
+// OPTIONS adds a new route with the OPTIONS method to the group.
+func (group *RouterGroup) OPTIONS(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return group.addRoute([]string{MethodOptions}, path, handler, middlewares...)
+}
+
+// CONNECT adds a new route with the CONNECT method to the group.
+func (group *RouterGroup) CONNECT(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return group.addRoute([]string{MethodConnect}, path, handler, middlewares...)
+}
+
+// TRACE adds a new route with the TRACE method to the group.
+func (group *RouterGroup) TRACE(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return group.addRoute([]string{MethodTrace}, path, handler, middlewares...)
+}
+
+// Any adds a new route to the group for every HTTP method.
+func (group *RouterGroup) Any(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return group.Match(anyMethods, path, handler, middlewares...)
+}
+
+// Match adds a new route to the group for each of the given methods.
+func (group *RouterGroup) Match(methods []string, path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return group.addRoute(methods, path, handler, middlewares...)
 }
@@ -0,0 +1,144 @@
+package router
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// hostRoute holds the routes served only for requests whose host matches a
+// pattern such as "{tenant}.example.com".
+type hostRoute struct {
+	pattern string
+	regex   *regexp.Regexp
+	names   []string
+	tree    *Tree
+}
+
+// newHostRoute compiles a host pattern. Each "{name}" placeholder matches one
+// label of the host name; everything else is matched literally and without
+// regard to case.
+func newHostRoute(pattern string, tree *Tree) (*hostRoute, error) {
+	h := &hostRoute{pattern: pattern, tree: tree}
+
+	var expr strings.Builder
+	expr.WriteString("(?i)^")
+	for rest := pattern; rest != ""; {
+		start := strings.IndexByte(rest, '{')
+		if start == -1 {
+			expr.WriteString(regexp.QuoteMeta(rest))
+			break
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end == -1 {
+			return nil, fmt.Errorf("unterminated placeholder in host %q", pattern)
+		}
+		end += start
+
+		name := rest[start+1 : end]
+		if name == "" {
+			return nil, fmt.Errorf("placeholder without a name in host %q", pattern)
+		}
+		if contains(h.names, name) {
+			return nil, fmt.Errorf("placeholder %q repeated in host %q", name, pattern)
+		}
+		expr.WriteString(regexp.QuoteMeta(rest[:start]))
+		expr.WriteString("([^.]+)")
+		h.names = append(h.names, name)
+		rest = rest[end+1:]
+	}
+	expr.WriteString("$")
+
+	var err error
+	if h.regex, err = regexp.Compile(expr.String()); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// match reports whether host matches the pattern and appends the values of
+// its placeholders to params.
+func (h *hostRoute) match(host string, params *[]paramValue) bool {
+	if len(h.names) == 0 {
+		return strings.EqualFold(host, h.pattern)
+	}
+
+	values := h.regex.FindStringSubmatch(host)
+	if values == nil {
+		return false
+	}
+	for i, name := range h.names {
+		*params = append(*params, paramValue{name: name, value: values[i+1]})
+	}
+	return true
+}
+
+// Host returns a group whose routes are only served for requests whose host
+// matches the pattern. Placeholders such as "{tenant}" match one label of the
+// host name and their values are available as path params, so the group's
+// routes cannot use their names for params. Literal hosts are tried before
+// hosts with placeholders, which are tried in the order they were added;
+// requests for hosts that match none of them are served by the routes
+// registered directly on the router.
+//
+//	tenants := r.Host("{tenant}.example.com")
+//	tenants.GET("/dashboard", func(c *nex.Context) {
+//		tenant := c.PathParam.Get("tenant")
+//	})
+func (r *Router) Host(pattern string) *RouterGroup {
+	for _, h := range r.hosts {
+		if h.pattern == pattern {
+			return &RouterGroup{router: r, tree: h.tree, host: pattern}
+		}
+	}
+
+	tree := NewTree()
+	tree.paramTypes = r.tree.paramTypes
+	tree.strictSlash = r.tree.strictSlash
+
+	h, err := newHostRoute(pattern, tree)
+	if err != nil {
+		r.addError(&RouteError{Path: pattern, Err: fmt.Errorf("%w: %v", ErrInvalidPattern, err)})
+		tree.detached = true
+		return &RouterGroup{router: r, tree: tree, host: pattern}
+	}
+	tree.reserved = h.names
+
+	if len(h.names) == 0 {
+		// literal hosts come first so placeholders cannot shadow them
+		i := 0
+		for i < len(r.hosts) && len(r.hosts[i].names) == 0 {
+			i++
+		}
+		r.hosts = append(r.hosts[:i], append([]*hostRoute{h}, r.hosts[i:]...)...)
+	} else {
+		for _, other := range r.hosts {
+			if other.regex.String() == h.regex.String() {
+				r.addError(&RouteError{Path: pattern, Err: fmt.Errorf("%w: host %q is shadowed by %q", ErrAmbiguousRoute, pattern, other.pattern)})
+				break
+			}
+		}
+		r.hosts = append(r.hosts, h)
+	}
+	return &RouterGroup{router: r, tree: tree, host: pattern}
+}
+
+// treeFor returns the tree serving the host and appends the params captured
+// from it to params. Hosts that match no host pattern are served by the
+// router's own tree.
+func (r *Router) treeFor(host string, params *[]paramValue) *Tree {
+	if len(r.hosts) == 0 {
+		return r.tree
+	}
+
+	if i := strings.LastIndexByte(host, ':'); i != -1 && !strings.Contains(host[i:], "]") {
+		host = host[:i]
+	}
+
+	for _, h := range r.hosts {
+		if h.match(host, params) {
+			return h.tree
+		}
+	}
+	return r.tree
+}
@@ -0,0 +1,99 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	nexctx "github.com/nex-gen-tech/nex/context"
+)
+
+func TestHosts(t *testing.T) {
+	reply := func(text string) HandlerFunc {
+		return func(c *nexctx.Context) { c.Response.Write([]byte(text + c.Params.Get("tenant"))) }
+	}
+
+	r := NewRouter()
+	r.GET("/users", reply("default"))
+	r.Host("{tenant}.example.com").GET("/users", reply("tenant:"))
+	r.Host("api.example.com").GET("/users", reply("api"))
+	r.Host("{tenant}.example.org").GET("/users/:id", reply("org:"))
+	if err := r.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		host string
+		path string
+		code int
+		body string
+	}{
+		{host: "api.example.com", path: "/users", code: http.StatusOK, body: "api"},
+		{host: "API.Example.com:8080", path: "/users", code: http.StatusOK, body: "api"},
+		{host: "acme.example.com", path: "/users", code: http.StatusOK, body: "tenant:acme"},
+		{host: "acme.example.com:443", path: "/users", code: http.StatusOK, body: "tenant:acme"},
+		{host: "acme.example.org", path: "/users/1", code: http.StatusOK, body: "org:acme"},
+		{host: "a.b.example.com", path: "/users", code: http.StatusOK, body: "default"},
+		{host: "localhost", path: "/users", code: http.StatusOK, body: "default"},
+		{host: "acme.example.org", path: "/users", code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(MethodGet, tt.path, nil)
+		req.Host = tt.host
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.code {
+			t.Errorf("GET %s%s: status = %d, want %d", tt.host, tt.path, w.Code, tt.code)
+			continue
+		}
+		if tt.code == http.StatusOK && w.Body.String() != tt.body {
+			t.Errorf("GET %s%s: body = %q, want %q", tt.host, tt.path, w.Body.String(), tt.body)
+		}
+	}
+}
+
+func TestHostErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(r *Router)
+		err   error
+	}{
+		{
+			name:  "unterminated placeholder",
+			setup: func(r *Router) { r.Host("{tenant.example.com") },
+			err:   ErrInvalidPattern,
+		},
+		{
+			name:  "repeated placeholder",
+			setup: func(r *Router) { r.Host("{a}.{a}.example.com") },
+			err:   ErrInvalidPattern,
+		},
+		{
+			name: "shadowed placeholder host",
+			setup: func(r *Router) {
+				r.Host("{tenant}.example.com")
+				r.Host("{org}.example.com")
+			},
+			err: ErrAmbiguousRoute,
+		},
+		{
+			name:  "param named like a placeholder",
+			setup: func(r *Router) { r.Host("{tenant}.example.com").GET("/t/:tenant", nil) },
+			err:   ErrParamConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRouter()
+			tt.setup(r)
+			err := r.Validate()
+			if errs, _ := err.(RouteErrors); len(errs) != 1 || !errors.Is(errs[0], tt.err) {
+				t.Errorf("Validate() = %v, want one %v", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package router
+
+import (
+	"reflect"
+	"runtime"
+)
+
+// RouteInfo describes a registered route for one of its methods.
+type RouteInfo struct {
+	Method      string
+	Path        string
+	Host        string // host pattern, empty for routes served for any host
+	Group       string // prefix of the RouterGroup the route was added to
+	Name        string
+	Params      []string
+	Handler     string
+	Middlewares []string // global middlewares first, then group and route ones
+	Tags        []string
+	Meta        map[string]any
+}
+
+// Tag attaches tags to the route, for example to group it in generated docs.
+// Tags are reported by Router.Routes and don't affect matching.
+func (rt *Route) Tag(tags ...string) *Route {
+	rt.tags = append(rt.tags, tags...)
+	return rt
+}
+
+// Meta attaches a value to the route under key. Metadata is reported by
+// Router.Routes and doesn't affect matching.
+func (rt *Route) Meta(key string, value any) *Route {
+	if rt.meta == nil {
+		rt.meta = make(map[string]any)
+	}
+	rt.meta[key] = value
+	return rt
+}
+
+// Routes returns the registered routes in registration order, one entry per
+// method. Routes that failed to register, including the ones added to an
+// invalid Host, are left out.
+//
+//	for _, info := range r.Routes() {
+//		fmt.Println(info.Method, info.Path, info.Handler)
+//	}
+func (r *Router) Routes() []RouteInfo {
+	global := funcNames(r.middlewares)
+
+	var infos []RouteInfo
+	for _, rt := range r.routes {
+		params := rt.paramNames()
+		handler := funcName(rt.handler)
+		middlewares := append(global[:len(global):len(global)], funcNames(rt.middlewares)...)
+
+		for _, method := range rt.methods {
+			infos = append(infos, RouteInfo{
+				Method:      method,
+				Path:        rt.path,
+				Host:        rt.host,
+				Group:       rt.group,
+				Name:        rt.name,
+				Params:      params,
+				Handler:     handler,
+				Middlewares: middlewares,
+				Tags:        rt.tags,
+				Meta:        rt.meta,
+			})
+		}
+	}
+	return infos
+}
+
+// paramNames returns the names of the route's params in path order,
+// optional ones included.
+func (rt *Route) paramNames() []string {
+	if len(rt.variants) == 0 {
+		return nil
+	}
+
+	var names []string
+	for _, part := range rt.variants[len(rt.variants)-1] {
+		if part.param != nil {
+			names = append(names, part.param.name)
+		}
+	}
+	return names
+}
+
+// funcName returns the fully qualified name of a function.
+func funcName(fn any) string {
+	return runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+}
+
+// funcNames returns the names of the middlewares.
+func funcNames(middlewares []MiddlewareFunc) []string {
+	names := make([]string, len(middlewares))
+	for i, middleware := range middlewares {
+		names[i] = funcName(middleware)
+	}
+	return names
+}
@@ -0,0 +1,80 @@
+package router
+
+import (
+	"reflect"
+	"testing"
+
+	nexctx "github.com/nex-gen-tech/nex/context"
+)
+
+func introspectHandler(c *nexctx.Context) {}
+
+func introspectGlobal(next HandlerFunc) HandlerFunc { return next }
+
+func introspectGroup(next HandlerFunc) HandlerFunc { return next }
+
+func introspectRoute(next HandlerFunc) HandlerFunc { return next }
+
+func TestRoutes(t *testing.T) {
+	const pkg = "github.com/nex-gen-tech/nex/router."
+
+	r := NewRouter()
+	r.Use(introspectGlobal)
+	api := r.Group("/api")
+	api.Use(introspectGroup)
+	api.Match([]string{MethodGet, MethodPost}, "/users/:id<int>/posts[/:page]", introspectHandler, introspectRoute).
+		Name("posts").Tag("users").Meta("auth", true)
+	r.Host("{tenant}.example.com").GET("/home", introspectHandler)
+	r.GET("/u/:id", introspectHandler)
+	r.GET("/u/:uid/x", introspectHandler) // fails: conflicting param names
+	r.Host("{broken.example.com").GET("/lost", introspectHandler)
+
+	want := []RouteInfo{
+		{
+			Method:      MethodGet,
+			Path:        "/api/users/:id<int>/posts[/:page]",
+			Group:       "/api",
+			Name:        "posts",
+			Params:      []string{"id", "page"},
+			Handler:     pkg + "introspectHandler",
+			Middlewares: []string{pkg + "introspectGlobal", pkg + "introspectGroup", pkg + "introspectRoute"},
+			Tags:        []string{"users"},
+			Meta:        map[string]any{"auth": true},
+		},
+		{
+			Method:      MethodPost,
+			Path:        "/api/users/:id<int>/posts[/:page]",
+			Group:       "/api",
+			Name:        "posts",
+			Params:      []string{"id", "page"},
+			Handler:     pkg + "introspectHandler",
+			Middlewares: []string{pkg + "introspectGlobal", pkg + "introspectGroup", pkg + "introspectRoute"},
+			Tags:        []string{"users"},
+			Meta:        map[string]any{"auth": true},
+		},
+		{
+			Method:      MethodGet,
+			Path:        "/home",
+			Host:        "{tenant}.example.com",
+			Handler:     pkg + "introspectHandler",
+			Middlewares: []string{pkg + "introspectGlobal"},
+		},
+		{
+			Method:      MethodGet,
+			Path:        "/u/:id",
+			Params:      []string{"id"},
+			Handler:     pkg + "introspectHandler",
+			Middlewares: []string{pkg + "introspectGlobal"},
+		},
+	}
+
+	got := r.Routes()
+	if len(got) != len(want) {
+		t.Fatalf("Routes() returned %d routes, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("Routes()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package router
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	nexctx "github.com/nex-gen-tech/nex/context"
+)
+
+// Headers restricts the route to requests carrying the given header
+// key/value pairs. Several routes can share a method and path when their
+// matchers tell them apart; a request matching none of them gets a 406.
+//
+//	r.GET("/reports", reportsV2).Headers("X-Api-Version", "2")
+//	r.GET("/reports", reports)
+func (rt *Route) Headers(pairs ...string) *Route {
+	if !rt.checkPairs("Headers", pairs) {
+		return rt
+	}
+	for i := 0; i < len(pairs); i += 2 {
+		rt.headers = append(rt.headers, http.CanonicalHeaderKey(pairs[i]), pairs[i+1])
+	}
+	return rt
+}
+
+// Queries restricts the route to requests whose query string holds the given
+// key/value pairs. An empty value only requires the key to be present. A
+// request matching none of the routes for its path gets a 404.
+func (rt *Route) Queries(pairs ...string) *Route {
+	if !rt.checkPairs("Queries", pairs) {
+		return rt
+	}
+	rt.queries = append(rt.queries, pairs...)
+	return rt
+}
+
+// Consumes restricts the route to requests whose Content-Type is one of the
+// given media types, such as "application/json" or "image/*". A request
+// matching none of the routes for its path gets a 415.
+func (rt *Route) Consumes(mediaTypes ...string) *Route {
+	rt.consumes = append(rt.consumes, lowerAll(mediaTypes)...)
+	return rt
+}
+
+// Produces restricts the route to requests whose Accept header allows one of
+// the given media types, such as "application/vnd.x.v2+json". Requests
+// without an Accept header accept anything. A request matching none of the
+// routes for its path gets a 406.
+func (rt *Route) Produces(mediaTypes ...string) *Route {
+	rt.produces = append(rt.produces, lowerAll(mediaTypes)...)
+	return rt
+}
+
+// checkPairs records an error on the router if pairs holds an odd number of
+// values.
+func (rt *Route) checkPairs(matcher string, pairs []string) bool {
+	if len(pairs)%2 == 0 {
+		return true
+	}
+	if rt.router != nil {
+		rt.router.addError(&RouteError{
+			Methods: rt.methods,
+			Path:    rt.path,
+			Err:     fmt.Errorf("%w: %s needs key/value pairs", ErrInvalidPattern, matcher),
+		})
+	}
+	return false
+}
+
+// hasMatchers reports whether the route only serves some of the requests for
+// its method and path.
+func (rt *Route) hasMatchers() bool {
+	return len(rt.headers) > 0 || len(rt.queries) > 0 || len(rt.consumes) > 0 || len(rt.produces) > 0
+}
+
+// matchersKey returns a canonical description of the route's matchers. Two
+// routes for the same method and path with the same key are duplicates.
+func (rt *Route) matchersKey() string {
+	headers := joinPairs(rt.headers)
+	queries := joinPairs(rt.queries)
+	consumes := append([]string(nil), rt.consumes...)
+	produces := append([]string(nil), rt.produces...)
+	sort.Strings(headers)
+	sort.Strings(queries)
+	sort.Strings(consumes)
+	sort.Strings(produces)
+	return strings.Join([]string{
+		strings.Join(headers, "&"),
+		strings.Join(queries, "&"),
+		strings.Join(consumes, ","),
+		strings.Join(produces, ","),
+	}, "|")
+}
+
+// match reports whether the request satisfies the route's matchers. When it
+// does not, it returns the status the request should be answered with.
+func (rt *Route) match(req *http.Request) (int, bool) {
+	for i := 0; i < len(rt.queries); i += 2 {
+		values, ok := req.URL.Query()[rt.queries[i]]
+		if !ok || (rt.queries[i+1] != "" && !contains(values, rt.queries[i+1])) {
+			return http.StatusNotFound, false
+		}
+	}
+
+	if len(rt.consumes) > 0 {
+		mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		if err != nil || !matchMediaType(rt.consumes, mediaType) {
+			return http.StatusUnsupportedMediaType, false
+		}
+	}
+
+	for i := 0; i < len(rt.headers); i += 2 {
+		if !contains(req.Header.Values(rt.headers[i]), rt.headers[i+1]) {
+			return http.StatusNotAcceptable, false
+		}
+	}
+
+	if len(rt.produces) > 0 && !accepts(req.Header.Values("Accept"), rt.produces) {
+		return http.StatusNotAcceptable, false
+	}
+	return 0, true
+}
+
+// selectRoute picks the route serving the request among the routes
+// registered for its method and path. Routes with matchers are tried in
+// registration order before the ones without. If none matches, it returns
+// the status to answer with: 415 takes precedence over 406, which takes
+// precedence over 404.
+func selectRoute(routes []*Route, req *http.Request) (*Route, int) {
+	status := 0
+	var fallback *Route
+	for _, rt := range routes {
+		if !rt.hasMatchers() {
+			if fallback == nil {
+				fallback = rt
+			}
+			continue
+		}
+		code, ok := rt.match(req)
+		if ok {
+			return rt, 0
+		}
+		if statusPriority(code) > statusPriority(status) {
+			status = code
+		}
+	}
+	if fallback != nil {
+		return fallback, 0
+	}
+	return nil, status
+}
+
+// statusPriority orders the statuses returned by Route.match.
+func statusPriority(status int) int {
+	switch status {
+	case http.StatusUnsupportedMediaType:
+		return 3
+	case http.StatusNotAcceptable:
+		return 2
+	case http.StatusNotFound:
+		return 1
+	}
+	return 0
+}
+
+// accepts reports whether the Accept header values allow one of the media
+// types. Entries with a quality of zero are ignored.
+func accepts(header []string, mediaTypes []string) bool {
+	if len(header) == 0 {
+		return true
+	}
+	for _, value := range header {
+		for _, entry := range strings.Split(value, ",") {
+			accepted, params, err := mime.ParseMediaType(strings.TrimSpace(entry))
+			if err != nil {
+				continue
+			}
+			if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q == 0 {
+				continue
+			}
+			for _, mediaType := range mediaTypes {
+				if matchMediaType([]string{accepted}, mediaType) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// matchMediaType reports whether mediaType matches one of the patterns, which
+// may use "*/*" or "type/*" wildcards.
+func matchMediaType(patterns []string, mediaType string) bool {
+	for _, pattern := range patterns {
+		if pattern == "*/*" || pattern == mediaType {
+			return true
+		}
+		if prefix := strings.TrimSuffix(pattern, "*"); prefix != pattern && strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// joinPairs returns key/value pairs as "key=value" strings.
+func joinPairs(pairs []string) []string {
+	joined := make([]string, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		joined = append(joined, pairs[i]+"="+pairs[i+1])
+	}
+	return joined
+}
+
+// contains reports whether values holds value.
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// lowerAll returns the strings lowercased.
+func lowerAll(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, v := range values {
+		lowered[i] = strings.ToLower(v)
+	}
+	return lowered
+}
+
+// notAcceptableHandler is the default handler for requests no route variant
+// can produce a response for.
+func notAcceptableHandler(c *nexctx.Context) {
+	http.Error(c.Response, http.StatusText(http.StatusNotAcceptable), http.StatusNotAcceptable)
+}
+
+// unsupportedMediaTypeHandler is the default handler for requests whose
+// Content-Type no route variant consumes.
+func unsupportedMediaTypeHandler(c *nexctx.Context) {
+	http.Error(c.Response, http.StatusText(http.StatusUnsupportedMediaType), http.StatusUnsupportedMediaType)
+}
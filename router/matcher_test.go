@@ -0,0 +1,140 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	nexctx "github.com/nex-gen-tech/nex/context"
+)
+
+func TestRouteMatchers(t *testing.T) {
+	reply := func(text string) HandlerFunc {
+		return func(c *nexctx.Context) { c.Response.Write([]byte(text)) }
+	}
+
+	r := NewRouter()
+	r.GET("/reports", reply("v2")).Headers("X-Api-Version", "2")
+	r.GET("/reports", reply("csv")).Queries("format", "csv")
+	r.GET("/reports", reply("plain"))
+	r.GET("/search", reply("search")).Queries("q", "")
+	r.POST("/uploads", reply("json")).Consumes("application/json")
+	r.POST("/uploads", reply("image")).Consumes("image/*")
+	r.GET("/docs", reply("pdf")).Produces("application/pdf")
+	r.GET("/docs", reply("html")).Produces("text/html")
+	r.PUT("/items", reply("put")).Consumes("application/json").Headers("X-Api-Version", "2")
+	if err := r.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		headers map[string]string
+		code    int
+		body    string
+	}{
+		{name: "header", method: MethodGet, target: "/reports", headers: map[string]string{"X-Api-Version": "2"}, code: http.StatusOK, body: "v2"},
+		{name: "query", method: MethodGet, target: "/reports?format=csv", code: http.StatusOK, body: "csv"},
+		{name: "plain fallback", method: MethodGet, target: "/reports?format=xml", code: http.StatusOK, body: "plain"},
+		{name: "first matching route wins", method: MethodGet, target: "/reports?format=csv", headers: map[string]string{"X-Api-Version": "2"}, code: http.StatusOK, body: "v2"},
+		{name: "query key only", method: MethodGet, target: "/search?q=", code: http.StatusOK, body: "search"},
+		{name: "query key missing", method: MethodGet, target: "/search", code: http.StatusNotFound},
+		{name: "consumes", method: MethodPost, target: "/uploads", headers: map[string]string{"Content-Type": "application/json; charset=utf-8"}, code: http.StatusOK, body: "json"},
+		{name: "consumes wildcard", method: MethodPost, target: "/uploads", headers: map[string]string{"Content-Type": "image/png"}, code: http.StatusOK, body: "image"},
+		{name: "unsupported media type", method: MethodPost, target: "/uploads", headers: map[string]string{"Content-Type": "text/plain"}, code: http.StatusUnsupportedMediaType},
+		{name: "missing content type", method: MethodPost, target: "/uploads", code: http.StatusUnsupportedMediaType},
+		{name: "produces", method: MethodGet, target: "/docs", headers: map[string]string{"Accept": "text/html;q=0.9, */*;q=0"}, code: http.StatusOK, body: "html"},
+		{name: "produces without accept", method: MethodGet, target: "/docs", code: http.StatusOK, body: "pdf"},
+		{name: "not acceptable", method: MethodGet, target: "/docs", headers: map[string]string{"Accept": "image/png"}, code: http.StatusNotAcceptable},
+		{name: "415 before 406", method: MethodPut, target: "/items", headers: map[string]string{"Content-Type": "text/plain"}, code: http.StatusUnsupportedMediaType},
+		{name: "406 when media type matches", method: MethodPut, target: "/items", headers: map[string]string{"Content-Type": "application/json"}, code: http.StatusNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.code {
+				t.Errorf("%s %s: status = %d, want %d", tt.method, tt.target, w.Code, tt.code)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("%s %s: body = %q, want %q", tt.method, tt.target, w.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestRouteMatchersDuplicates(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(r *Router)
+		err   error
+	}{
+		{
+			name: "same matchers in another order",
+			setup: func(r *Router) {
+				r.GET("/a", nil).Headers("X-A", "1", "X-B", "2")
+				r.GET("/a", nil).Headers("x-b", "2", "x-a", "1")
+			},
+			err: ErrDuplicateRoute,
+		},
+		{
+			name: "same media types in another order",
+			setup: func(r *Router) {
+				r.POST("/a", nil).Consumes("application/json", "text/xml")
+				r.POST("/a", nil).Consumes("TEXT/XML", "application/json")
+			},
+			err: ErrDuplicateRoute,
+		},
+		{
+			name: "two plain routes",
+			setup: func(r *Router) {
+				r.GET("/a", nil)
+				r.GET("/a", nil)
+			},
+			err: ErrDuplicateRoute,
+		},
+		{
+			name: "different matchers",
+			setup: func(r *Router) {
+				r.GET("/a", nil).Queries("v", "1")
+				r.GET("/a", nil).Queries("v", "2")
+				r.GET("/a", nil)
+			},
+		},
+		{
+			name: "odd matcher pairs",
+			setup: func(r *Router) {
+				r.GET("/a", nil).Headers("X-A")
+			},
+			err: ErrInvalidPattern,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRouter()
+			tt.setup(r)
+			err := r.Validate()
+			if first := firstError(err); (tt.err == nil && err != nil) || !errors.Is(first, tt.err) {
+				t.Errorf("Validate() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+// firstError returns the first error collected by Validate, or nil.
+func firstError(err error) error {
+	if errs, ok := err.(RouteErrors); ok && len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	nexctx "github.com/nex-gen-tech/nex/context"
+)
+
+// Mount serves every request under prefix with handler, for example a
+// metrics endpoint or another Router built as a separate module. The prefix
+// is stripped from the request path before it is forwarded, so the handler
+// sees "/admin/users" mounted at "/admin" as "/users". Mounted handlers run
+// behind the global middlewares. The prefix must be static.
+//
+//	r.Mount("/admin", adminRouter)
+func (r *Router) Mount(prefix string, handler http.Handler, middlewares ...MiddlewareFunc) {
+	r.mount(r.tree, "", "", prefix, mountHandler(prefix, handler), middlewares)
+}
+
+// Handle is like Mount but forwards requests with their path unchanged, for
+// handlers that expect the full path, such as pprof, which registers its
+// handlers under "/debug/pprof/" on http.DefaultServeMux.
+//
+//	r.Handle("/debug/pprof", http.DefaultServeMux)
+func (r *Router) Handle(prefix string, handler http.Handler, middlewares ...MiddlewareFunc) {
+	r.mount(r.tree, "", "", prefix, WrapHandler(handler), middlewares)
+}
+
+// Mount serves every request under the group's prefix followed by prefix
+// with handler. The full prefix is stripped from the request path before it
+// is forwarded, and the handler runs behind the group's middlewares.
+func (group *RouterGroup) Mount(prefix string, handler http.Handler, middlewares ...MiddlewareFunc) {
+	group.mount(prefix, mountHandler(group.prefix+prefix, handler), middlewares)
+}
+
+// Handle is like Mount but forwards requests with their path unchanged.
+func (group *RouterGroup) Handle(prefix string, handler http.Handler, middlewares ...MiddlewareFunc) {
+	group.mount(prefix, WrapHandler(handler), middlewares)
+}
+
+// mount registers forward for the group's prefix followed by prefix and
+// everything below it.
+func (group *RouterGroup) mount(prefix string, forward HandlerFunc, middlewares []MiddlewareFunc) {
+	tree := group.tree
+	if tree == nil {
+		tree = group.router.tree
+	}
+
+	allMiddlewares := append(group.middlewares[:len(group.middlewares):len(group.middlewares)], middlewares...)
+	group.router.mount(tree, group.host, group.prefix, group.prefix+prefix, forward, allMiddlewares)
+}
+
+// mount registers forward for the prefix and everything below it.
+func (r *Router) mount(tree *Tree, host, group, prefix string, forward HandlerFunc, middlewares []MiddlewareFunc) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if strings.ContainsAny(prefix, ":*[(<{") {
+		r.addError(&RouteError{Path: prefix, Err: fmt.Errorf("%w: mount prefix must be static", ErrInvalidPattern)})
+		return
+	}
+
+	root := prefix
+	if root == "" {
+		root = "/"
+	}
+	r.addRoute(tree, host, group, anyMethods, root, forward, middlewares)
+	r.addRoute(tree, host, group, anyMethods, prefix+"/*", forward, middlewares)
+}
+
+// mountHandler returns a handler forwarding requests to h with prefix
+// stripped from their path.
+func mountHandler(prefix string, h http.Handler) HandlerFunc {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return func(c *nexctx.Context) {
+		req := c.Request
+		u := *req.URL
+		u.Path = stripPrefix(u.Path, prefix)
+		if u.RawPath != "" {
+			u.RawPath = stripPrefix(u.RawPath, prefix)
+		}
+
+		forwarded := new(http.Request)
+		*forwarded = *req
+		forwarded.URL = &u
+		h.ServeHTTP(c.Response, forwarded)
+	}
+}
+
+// stripPrefix removes prefix from path, keeping the result rooted.
+func stripPrefix(path, prefix string) string {
+	path = strings.TrimPrefix(path, prefix)
+	if path == "" || path[0] != '/' {
+		return "/" + path
+	}
+	return path
+}
+
+// WrapHandler adapts a http.Handler to a HandlerFunc.
+func WrapHandler(h http.Handler) HandlerFunc {
+	return func(c *nexctx.Context) {
+		h.ServeHTTP(c.Response, c.Request)
+	}
+}
+
+// WrapMiddleware adapts a net/http middleware, such as one from a third-party
+// package, to a MiddlewareFunc. Changes it makes to the response writer or
+// the request are seen by the handlers that follow. The middleware is built
+// once per route and finds the Context through the request's context, so it
+// must pass on that context or one derived from it.
+//
+//	r.Use(router.WrapMiddleware(handlers.ProxyHeaders))
+func WrapMiddleware(m func(http.Handler) http.Handler) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		h := m(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			c, ok := req.Context().Value(contextKey{}).(*nexctx.Context)
+			if !ok {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			c.Response = w
+			c.Request = req
+			next(c)
+		}))
+		return func(c *nexctx.Context) {
+			req := c.Request.WithContext(context.WithValue(c.Request.Context(), contextKey{}, c))
+			h.ServeHTTP(c.Response, req)
+		}
+	}
+}
+
+// contextKey is the key WrapMiddleware stores the Context under in the
+// request's context.
+type contextKey struct{}
+
+// HTTPMiddleware adapts a MiddlewareFunc to a net/http middleware, so
+// interceptors can wrap handlers that are not served by a Router.
+//
+//	http.Handle("/metrics", router.HTTPMiddleware(interceptor.Logging())(promhttp.Handler()))
+func HTTPMiddleware(m MiddlewareFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		handler := m(WrapHandler(next))
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			ctx := nexctx.AcquireContext(w, req)
+			defer nexctx.ReleaseContext(ctx)
+
+			handler(ctx)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	_ "net/http/pprof"
+	"testing"
+
+	nexctx "github.com/nex-gen-tech/nex/context"
+)
+
+func TestWrapMiddleware(t *testing.T) {
+	built := 0
+	m := func(next http.Handler) http.Handler {
+		built++
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("X-Wrapped", "1")
+			req.Header.Set("X-Forwarded", "1")
+			next.ServeHTTP(w, req)
+		})
+	}
+
+	r := NewRouter()
+	r.Use(WrapMiddleware(m))
+	r.GET("/users/:id", func(c *nexctx.Context) {
+		c.Response.Header().Set("X-Id", c.Params.Get("id"))
+		c.Response.Header().Set("X-Forwarded", c.Request.Header.Get("X-Forwarded"))
+	})
+	built = 0
+
+	for _, id := range []string{"1", "2", "3"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(MethodGet, "/users/"+id, nil))
+
+		if got := w.Header().Get("X-Id"); got != id {
+			t.Errorf("GET /users/%s: id = %q, want %q", id, got, id)
+		}
+		if w.Header().Get("X-Wrapped") != "1" || w.Header().Get("X-Forwarded") != "1" {
+			t.Errorf("GET /users/%s: changes made by the middleware were lost", id)
+		}
+	}
+	if built != 0 {
+		t.Errorf("middleware built %d times while serving, want 0", built)
+	}
+}
+
+func TestMount(t *testing.T) {
+	echoPath := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(req.URL.Path))
+	})
+
+	billing := NewRouter()
+	billing.GET("/", func(c *nexctx.Context) { c.Response.Write([]byte("billing home")) })
+	billing.GET("/invoices/:id", func(c *nexctx.Context) {
+		c.Response.Write([]byte("invoice " + c.Params.Get("id")))
+	})
+
+	r := NewRouter()
+	r.Mount("/static/", echoPath)
+	r.Handle("/raw", echoPath)
+	r.Group("/api").Mount("/billing", billing)
+	r.Handle("/debug/pprof", http.DefaultServeMux)
+	r.GET("/static-page", func(c *nexctx.Context) { c.Response.Write([]byte("page")) })
+	if err := r.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method string
+		path   string
+		code   int
+		body   string
+	}{
+		{method: MethodGet, path: "/static/css/site.css", code: http.StatusOK, body: "/css/site.css"},
+		{method: MethodGet, path: "/static", code: http.StatusOK, body: "/"},
+		{method: MethodGet, path: "/static/", code: http.StatusOK, body: "/"},
+		{method: MethodPost, path: "/static/a", code: http.StatusOK, body: "/a"},
+		{method: MethodGet, path: "/static-page", code: http.StatusOK, body: "page"},
+		{method: MethodGet, path: "/raw/a/b", code: http.StatusOK, body: "/raw/a/b"},
+		{method: MethodGet, path: "/api/billing", code: http.StatusOK, body: "billing home"},
+		{method: MethodGet, path: "/api/billing/invoices/7", code: http.StatusOK, body: "invoice 7"},
+		{method: MethodGet, path: "/api/billing/missing", code: http.StatusNotFound},
+		{method: MethodGet, path: "/debug/pprof/", code: http.StatusOK},
+		{method: MethodGet, path: "/debug/pprof/cmdline", code: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+		if w.Code != tt.code {
+			t.Errorf("%s %s: status = %d, want %d", tt.method, tt.path, w.Code, tt.code)
+			continue
+		}
+		if tt.body != "" && w.Body.String() != tt.body {
+			t.Errorf("%s %s: body = %q, want %q", tt.method, tt.path, w.Body.String(), tt.body)
+		}
+	}
+}
+
+func TestMountDynamicPrefix(t *testing.T) {
+	r := NewRouter()
+	r.Mount("/users/:id", http.NotFoundHandler())
+
+	err := r.Validate()
+	if errs, _ := err.(RouteErrors); len(errs) != 1 || !errors.Is(errs[0], ErrInvalidPattern) {
+		t.Errorf("Validate() = %v, want one %v", err, ErrInvalidPattern)
+	}
+}
@@ -1,6 +1,7 @@
 package router
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 )
@@ -9,42 +10,50 @@ const (
 	simpleParam paramType = iota
 	wildcardParam
 	regexParam
+	typedParam
 )
 
+// wildcardName is the param name of an unnamed catch-all ("*").
+const wildcardName = "*"
+
 var regexCache = make(map[string]*regexp.Regexp)
 
 type paramType int
 
 type paramMatcher struct {
-	ptype paramType
-	regex *regexp.Regexp
-	name  string
+	ptype      paramType
+	regex      *regexp.Regexp
+	typed      ParamMatcher
+	name       string
+	constraint string // "(regex)" or "<type>" as written in the pattern
 }
 
-func newParamMatcher(segment string) (*paramMatcher, error) {
+func newParamMatcher(segment string, types map[string]paramTypeFactory) (*paramMatcher, error) {
 	pm := &paramMatcher{}
 
-	if segment == "*" {
+	if segment[0] == '*' {
+		// a catch-all captures the rest of the path, slashes included
 		pm.ptype = wildcardParam
+		pm.name = segment[1:]
+		if pm.name == "" {
+			pm.name = wildcardName
+		}
 	} else if segment[0] == ':' {
-		idxStart, idxEnd := strings.Index(segment, "("), strings.LastIndex(segment, ")")
-		if idxStart != -1 && idxEnd != -1 {
-			pm.ptype = regexParam
-			pm.name = segment[1:idxStart]
-			regexPattern := segment[idxStart : idxEnd+1]
-			if cachedRegex, exists := regexCache[regexPattern]; exists {
-				pm.regex = cachedRegex
-			} else {
-				var err error
-				pm.regex, err = regexp.Compile("^" + regexPattern + "$")
-				if err != nil {
-					return nil, err
-				}
-				regexCache[regexPattern] = pm.regex
-			}
-		} else {
+		idx := strings.IndexAny(segment, "(<")
+		if idx == -1 {
 			pm.ptype = simpleParam
 			pm.name = segment[1:]
+			return pm, nil
+		}
+
+		pm.name = segment[1:idx]
+		pm.constraint = segment[idx:]
+		if segment[idx] == '(' {
+			if err := pm.compileRegex(); err != nil {
+				return nil, err
+			}
+		} else if err := pm.buildTyped(types); err != nil {
+			return nil, err
 		}
 	} else {
 		return nil, nil
@@ -53,14 +62,67 @@ func newParamMatcher(segment string) (*paramMatcher, error) {
 	return pm, nil
 }
 
-func (pm *paramMatcher) match(segment string) (bool, string) {
+// compileRegex compiles the "(regex)" constraint of the param.
+func (pm *paramMatcher) compileRegex() error {
+	regexPattern := pm.constraint
+	if !strings.HasSuffix(regexPattern, ")") {
+		return fmt.Errorf("unterminated regex in param %q", pm.name)
+	}
+
+	pm.ptype = regexParam
+	if cachedRegex, exists := regexCache[regexPattern]; exists {
+		pm.regex = cachedRegex
+		return nil
+	}
+
+	var err error
+	pm.regex, err = regexp.Compile("^" + regexPattern + "$")
+	if err != nil {
+		return err
+	}
+	regexCache[regexPattern] = pm.regex
+	return nil
+}
+
+// buildTyped builds the matcher of the "<type>" or "<type(args)>" constraint
+// of the param from the registered param types.
+func (pm *paramMatcher) buildTyped(types map[string]paramTypeFactory) error {
+	if !strings.HasSuffix(pm.constraint, ">") {
+		return fmt.Errorf("unterminated type in param %q", pm.name)
+	}
+
+	typeName, args := pm.constraint[1:len(pm.constraint)-1], []string(nil)
+	if idx := strings.IndexByte(typeName, '('); idx != -1 && strings.HasSuffix(typeName, ")") {
+		for _, arg := range strings.Split(typeName[idx+1:len(typeName)-1], ",") {
+			args = append(args, strings.TrimSpace(arg))
+		}
+		typeName = typeName[:idx]
+	}
+
+	factory, ok := types[typeName]
+	if !ok {
+		return fmt.Errorf("unknown type %q in param %q", typeName, pm.name)
+	}
+	typed, err := factory(args)
+	if err != nil {
+		return fmt.Errorf("type %q in param %q: %v", typeName, pm.name, err)
+	}
+
+	pm.ptype = typedParam
+	pm.typed = typed
+	return nil
+}
+
+// match reports whether the segment satisfies the param's constraint. Typed
+// params also return the segment converted to their type.
+func (pm *paramMatcher) match(segment string) (any, bool) {
 	switch pm.ptype {
 	case simpleParam, wildcardParam:
-		return true, segment
+		return nil, true
 	case regexParam:
-		if pm.regex.MatchString(segment) {
-			return true, segment
-		}
+		return nil, pm.regex.MatchString(segment)
+	case typedParam:
+		return pm.typed(segment)
 	}
-	return false, ""
+	return nil, false
 }
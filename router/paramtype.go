@@ -0,0 +1,108 @@
+package router
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ParamMatcher checks the value of a typed path param such as ":id<int>". It
+// returns whether the value is valid together with the value converted to
+// its type, which handlers can read without parsing it again.
+type ParamMatcher func(value string) (any, bool)
+
+// paramTypeFactory builds the matcher of a param type from the arguments
+// given in the route pattern, such as the bounds of ":n<range(1,100)>".
+type paramTypeFactory func(args []string) (ParamMatcher, error)
+
+// defaultParamTypes are the param types every router knows.
+var defaultParamTypes = map[string]paramTypeFactory{
+	"int":   withoutArgs(matchInt),
+	"uint":  withoutArgs(matchUint),
+	"uuid":  withoutArgs(matchUUID),
+	"alpha": withoutArgs(matchAlpha),
+	"date":  withoutArgs(matchDate),
+	"range": newRangeMatcher,
+}
+
+// RegisterParamType registers a param type that routes can use as
+// ":name<type>". Types must be registered before the routes using them.
+//
+//	r.RegisterParamType("slug", func(value string) (any, bool) {
+//		return nil, slugRegex.MatchString(value)
+//	})
+//	r.GET("/posts/:slug<slug>", showPost)
+func (r *Router) RegisterParamType(name string, matcher ParamMatcher) {
+	r.tree.paramTypes[name] = withoutArgs(matcher)
+}
+
+// withoutArgs wraps a matcher for a param type that takes no arguments.
+func withoutArgs(matcher ParamMatcher) paramTypeFactory {
+	return func(args []string) (ParamMatcher, error) {
+		if len(args) != 0 {
+			return nil, fmt.Errorf("param type takes no arguments, got %d", len(args))
+		}
+		return matcher, nil
+	}
+}
+
+// matchInt accepts signed integers and converts them to int.
+func matchInt(value string) (any, bool) {
+	n, err := strconv.Atoi(value)
+	return n, err == nil
+}
+
+// matchUint accepts unsigned integers and converts them to uint64.
+func matchUint(value string) (any, bool) {
+	n, err := strconv.ParseUint(value, 10, 64)
+	return n, err == nil
+}
+
+// matchUUID accepts UUIDs and converts them to uuid.UUID.
+func matchUUID(value string) (any, bool) {
+	id, err := uuid.Parse(value)
+	return id, err == nil
+}
+
+// matchAlpha accepts values made of ASCII letters only.
+func matchAlpha(value string) (any, bool) {
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return nil, false
+		}
+	}
+	return nil, value != ""
+}
+
+// matchDate accepts dates in the YYYY-MM-DD format and converts them to time.Time.
+func matchDate(value string) (any, bool) {
+	date, err := time.Parse("2006-01-02", value)
+	return date, err == nil
+}
+
+// newRangeMatcher builds the matcher of "range(min,max)", which accepts
+// integers between min and max inclusive and converts them to int.
+func newRangeMatcher(args []string) (ParamMatcher, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("range takes 2 arguments, got %d", len(args))
+	}
+	min, err := strconv.Atoi(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid range minimum %q", args[0])
+	}
+	max, err := strconv.Atoi(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid range maximum %q", args[1])
+	}
+	if min > max {
+		return nil, fmt.Errorf("range minimum %d is greater than maximum %d", min, max)
+	}
+
+	return func(value string) (any, bool) {
+		n, err := strconv.Atoi(value)
+		return n, err == nil && n >= min && n <= max
+	}, nil
+}
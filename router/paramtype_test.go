@@ -0,0 +1,124 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	nexctx "github.com/nex-gen-tech/nex/context"
+)
+
+func TestParamTypes(t *testing.T) {
+	id := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	date := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		pattern string
+		value   string
+		ok      bool
+		typed   any
+	}{
+		{pattern: "/p/:v<int>", value: "-42", ok: true, typed: -42},
+		{pattern: "/p/:v<int>", value: "4.2", ok: false},
+		{pattern: "/p/:v<uint>", value: "42", ok: true, typed: uint64(42)},
+		{pattern: "/p/:v<uint>", value: "-1", ok: false},
+		{pattern: "/p/:v<uuid>", value: id.String(), ok: true, typed: id},
+		{pattern: "/p/:v<uuid>", value: "not-a-uuid", ok: false},
+		{pattern: "/p/:v<alpha>", value: "abcXYZ", ok: true},
+		{pattern: "/p/:v<alpha>", value: "abc1", ok: false},
+		{pattern: "/p/:v<date>", value: "2024-02-29", ok: true, typed: date},
+		{pattern: "/p/:v<date>", value: "2023-02-29", ok: false},
+		{pattern: "/p/:v<range(1,10)>", value: "1", ok: true, typed: 1},
+		{pattern: "/p/:v<range(1,10)>", value: "10", ok: true, typed: 10},
+		{pattern: "/p/:v<range(1,10)>", value: "11", ok: false},
+		{pattern: "/p/:v<range(-5,-1)>", value: "-3", ok: true, typed: -3},
+	}
+
+	for _, tt := range tests {
+		tree := newTestTree(t, tt.pattern)
+		routes, values := tree.lookup(MethodGet, "/p/"+tt.value)
+		if (routes != nil) != tt.ok {
+			t.Errorf("%s: matched %q = %v, want %v", tt.pattern, tt.value, routes != nil, tt.ok)
+			continue
+		}
+		if tt.ok && values[0].typed != tt.typed {
+			t.Errorf("%s: typed value of %q = %#v, want %#v", tt.pattern, tt.value, values[0].typed, tt.typed)
+		}
+	}
+}
+
+func TestInvalidParamTypes(t *testing.T) {
+	patterns := []string{
+		"/p/:v<unknown>",
+		"/p/:v<int(1)>",
+		"/p/:v<range(1)>",
+		"/p/:v<range(1,2,3)>",
+		"/p/:v<range(a,2)>",
+		"/p/:v<range(1,b)>",
+		"/p/:v<range(5,1)>",
+	}
+	for _, pattern := range patterns {
+		if _, err := NewTree().AddRoute(MethodGet, pattern, nil); !errors.Is(err, ErrInvalidPattern) {
+			t.Errorf("AddRoute(%q) error = %v, want %v", pattern, err, ErrInvalidPattern)
+		}
+	}
+}
+
+func TestParamTypeFallThrough(t *testing.T) {
+	tree := newTestTree(t, "/n/:id<int>", "/n/:day<date>", "/n/:name")
+	checkMatches(t, tree, []match{
+		{path: "/n/42", route: "/n/:id<int>", params: map[string]string{"id": "42"}},
+		{path: "/n/2024-01-02", route: "/n/:day<date>", params: map[string]string{"day": "2024-01-02"}},
+		{path: "/n/abc", route: "/n/:name", params: map[string]string{"name": "abc"}},
+	})
+}
+
+func TestRegisterParamType(t *testing.T) {
+	r := NewRouter()
+	r.RegisterParamType("slug", func(value string) (any, bool) {
+		return strings.ToUpper(value), !strings.ContainsAny(value, " _")
+	})
+	r.GET("/posts/:slug<slug>", func(c *nexctx.Context) {
+		c.Response.Write([]byte(c.PathParam.Value("slug").(string)))
+	})
+	r.GET("/posts/:any", func(c *nexctx.Context) {
+		c.Response.Write([]byte("fallback"))
+	})
+	if err := r.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]string{"/posts/hello-world": "HELLO-WORLD", "/posts/hello_world": "fallback"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(MethodGet, path, nil))
+		if w.Body.String() != want {
+			t.Errorf("GET %s: body = %q, want %q", path, w.Body.String(), want)
+		}
+	}
+}
+
+func TestTypedParamValues(t *testing.T) {
+	r := NewRouter()
+	r.GET("/items/:id<range(1,100)>/:ref<uuid>", func(c *nexctx.Context) {
+		if _, ok := c.PathParam.Value("id").(int); !ok {
+			t.Errorf("Value(id) = %#v, want an int", c.PathParam.Value("id"))
+		}
+		if n, err := c.PathParam.GetAsInt("id"); err != nil || n != 7 {
+			t.Errorf("GetAsInt(id) = %d, %v, want 7", n, err)
+		}
+		if ref, err := c.PathParam.GetAsUUID("ref"); err != nil || ref.String() != "6ba7b810-9dad-11d1-80b4-00c04fd430c8" {
+			t.Errorf("GetAsUUID(ref) = %v, %v", ref, err)
+		}
+		c.Response.WriteHeader(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(MethodGet, "/items/7/6ba7b810-9dad-11d1-80b4-00c04fd430c8", nil))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
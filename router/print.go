@@ -0,0 +1,97 @@
+package router
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// methodColors are the colors of the methods in the route table.
+var methodColors = map[string]*color.Color{
+	MethodGet:     color.New(color.FgBlue, color.Bold),
+	MethodPost:    color.New(color.FgGreen, color.Bold),
+	MethodPut:     color.New(color.FgYellow, color.Bold),
+	MethodPatch:   color.New(color.FgCyan, color.Bold),
+	MethodDelete:  color.New(color.FgRed, color.Bold),
+	MethodHead:    color.New(color.FgMagenta, color.Bold),
+	MethodOptions: color.New(color.FgWhite, color.Bold),
+}
+
+var (
+	groupColor      = color.New(color.FgHiBlack, color.Bold)
+	handlerColor    = color.New(color.FgHiWhite)
+	middlewareColor = color.New(color.FgHiBlack)
+)
+
+// printRouteTable writes the registered routes to w as an aligned table,
+// grouped by host and group prefix. Middlewares are listed when
+// SetPrintMiddlewares is enabled.
+func (r *Router) printRouteTable(w io.Writer) {
+	infos := r.Routes()
+	if len(infos) == 0 {
+		return
+	}
+
+	var groups []string
+	byGroup := make(map[string][]RouteInfo)
+	methodWidth, pathWidth, handlerWidth := 0, 0, 0
+	for _, info := range infos {
+		key := strings.TrimSpace(info.Host + " " + info.Group)
+		if key == "" {
+			key = "/"
+		}
+		if _, ok := byGroup[key]; !ok {
+			groups = append(groups, key)
+		}
+		byGroup[key] = append(byGroup[key], info)
+
+		methodWidth = max(methodWidth, len(info.Method))
+		pathWidth = max(pathWidth, len(info.Path))
+		handlerWidth = max(handlerWidth, len(shortFuncName(info.Handler)))
+	}
+
+	r.log.InfoF("%d route(s) registered", len(infos))
+	for _, group := range groups {
+		fmt.Fprintf(w, "\n %s\n", groupColor.Sprint(group))
+		for _, info := range byGroup[group] {
+			methodColor, ok := methodColors[info.Method]
+			if !ok {
+				methodColor = color.New(color.Bold)
+			}
+
+			line := fmt.Sprintf("   %s %-*s  %s",
+				methodColor.Sprintf("%-*s", methodWidth, info.Method),
+				pathWidth, info.Path,
+				handlerColor.Sprintf("%-*s", handlerWidth, shortFuncName(info.Handler)),
+			)
+			if r.printMiddlewares && len(info.Middlewares) > 0 {
+				names := make([]string, len(info.Middlewares))
+				for i, name := range info.Middlewares {
+					names[i] = shortFuncName(name)
+				}
+				line += "  " + middlewareColor.Sprint(strings.Join(names, " > "))
+			}
+			fmt.Fprintln(w, strings.TrimRight(line, " "))
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// shortFuncName strips the package path from a function name, leaving the
+// package name, as in "handlers.ListUsers".
+func shortFuncName(name string) string {
+	if i := strings.LastIndexByte(name, '/'); i != -1 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// max returns the larger of a and b.
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,114 @@
+package router
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	nexctx "github.com/nex-gen-tech/nex/context"
+)
+
+// SetStrictSlash sets whether a trailing slash is significant, so "/a" and
+// "/a/" are different routes. By default trailing slashes are ignored and
+// both paths are served by the same route. It must be set before routes are
+// registered.
+func (r *Router) SetStrictSlash(strict bool) {
+	r.tree.strictSlash = strict
+	for _, h := range r.hosts {
+		h.tree.strictSlash = strict
+	}
+}
+
+// SetRedirectTrailingSlash sets whether, with strict slashes, a request that
+// matches no route is redirected to the same path with the trailing slash
+// added or removed when that path has a route.
+func (r *Router) SetRedirectTrailingSlash(redirect bool) {
+	r.redirectTrailingSlash = redirect
+}
+
+// SetRedirectCleanPath sets whether a request whose path is not clean, such
+// as "//a/../b", is redirected to its path.Clean form when no route matches
+// the path as requested but one matches the clean form.
+func (r *Router) SetRedirectCleanPath(redirect bool) {
+	r.redirectCleanPath = redirect
+}
+
+// SetRedirectCaseInsensitive sets whether a request that matches no route is
+// redirected to a route whose static text differs only in case, as in
+// "/Users/42" for "/users/:id".
+func (r *Router) SetRedirectCaseInsensitive(redirect bool) {
+	r.redirectCaseInsensitive = redirect
+}
+
+// redirectPath returns the canonical path to redirect an unmatched request
+// to, according to the router's redirect policies, or "" if there is none.
+func (r *Router) redirectPath(tree *Tree, method, reqPath string) string {
+	if method == MethodHead && r.handleHead {
+		method = MethodGet
+	}
+
+	if r.redirectTrailingSlash && tree.strictSlash && reqPath != "/" {
+		alt := reqPath + "/"
+		if strings.HasSuffix(reqPath, "/") {
+			alt = strings.TrimSuffix(reqPath, "/")
+		}
+		if rt, _ := tree.lookup(method, alt); rt != nil {
+			return alt
+		}
+	}
+
+	fixed := reqPath
+	if r.redirectCleanPath {
+		fixed = cleanPath(reqPath)
+		if fixed != reqPath {
+			if rt, _ := tree.lookup(method, fixed); rt != nil {
+				return fixed
+			}
+		}
+	}
+
+	if r.redirectCaseInsensitive {
+		if found, ok := tree.FindCaseInsensitive(method, fixed); ok && found != reqPath {
+			return found
+		}
+	}
+
+	return ""
+}
+
+// redirectTarget returns the escaped path to redirect an unmatched request
+// to, or "" if there is none. Paths starting with "//" are never redirected
+// to, since clients would take them for another host.
+func (r *Router) redirectTarget(tree *Tree, method, reqPath string) string {
+	target := r.redirectPath(tree, method, reqPath)
+	if target == "" || strings.HasPrefix(target, "//") {
+		return ""
+	}
+	if r.useRawPath {
+		// matched against the escaped path already
+		return target
+	}
+	return (&url.URL{Path: target}).EscapedPath()
+}
+
+// cleanPath applies path.Clean to p, keeping its trailing slash.
+func cleanPath(p string) string {
+	cleaned := path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+// redirectHandler answers a request with the redirect whose Location header
+// was set before the middlewares ran. GET and HEAD requests get 301 Moved
+// Permanently, other methods get 308 Permanent Redirect so clients repeat
+// them with the same method and body.
+func redirectHandler(c *nexctx.Context) {
+	code := http.StatusMovedPermanently
+	if c.Request.Method != MethodGet && c.Request.Method != MethodHead {
+		code = http.StatusPermanentRedirect
+	}
+	c.Response.WriteHeader(code)
+}
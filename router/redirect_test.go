@@ -0,0 +1,169 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	nexctx "github.com/nex-gen-tech/nex/context"
+)
+
+func TestRedirects(t *testing.T) {
+	ok := func(c *nexctx.Context) { c.Response.WriteHeader(http.StatusOK) }
+
+	tests := []struct {
+		name     string
+		setup    func(r *Router)
+		method   string
+		target   string
+		code     int
+		location string
+	}{
+		{
+			name:   "trailing slash ignored by default",
+			setup:  func(r *Router) { r.GET("/a", ok) },
+			method: MethodGet, target: "/a/", code: http.StatusOK,
+		},
+		{
+			name: "strict slash without redirect",
+			setup: func(r *Router) {
+				r.SetStrictSlash(true)
+				r.GET("/a", ok)
+			},
+			method: MethodGet, target: "/a/", code: http.StatusNotFound,
+		},
+		{
+			name: "trailing slash removed",
+			setup: func(r *Router) {
+				r.SetStrictSlash(true)
+				r.SetRedirectTrailingSlash(true)
+				r.GET("/a", ok)
+			},
+			method: MethodGet, target: "/a/?x=1", code: http.StatusMovedPermanently, location: "/a?x=1",
+		},
+		{
+			name: "trailing slash added",
+			setup: func(r *Router) {
+				r.SetStrictSlash(true)
+				r.SetRedirectTrailingSlash(true)
+				r.GET("/dir/", ok)
+			},
+			method: MethodGet, target: "/dir", code: http.StatusMovedPermanently, location: "/dir/",
+		},
+		{
+			name: "trailing slash with POST",
+			setup: func(r *Router) {
+				r.SetStrictSlash(true)
+				r.SetRedirectTrailingSlash(true)
+				r.POST("/a", ok)
+			},
+			method: MethodPost, target: "/a/", code: http.StatusPermanentRedirect, location: "/a",
+		},
+		{
+			name: "clean path",
+			setup: func(r *Router) {
+				r.SetRedirectCleanPath(true)
+				r.GET("/b", ok)
+			},
+			method: MethodGet, target: "//a/../b?q=go", code: http.StatusMovedPermanently, location: "/b?q=go",
+		},
+		{
+			name:   "clean path disabled",
+			setup:  func(r *Router) { r.GET("/b", ok) },
+			method: MethodGet, target: "/a/../b", code: http.StatusNotFound,
+		},
+		{
+			name: "case insensitive",
+			setup: func(r *Router) {
+				r.SetRedirectCaseInsensitive(true)
+				r.GET("/users/:id", ok)
+			},
+			method: MethodGet, target: "/Users/Ann", code: http.StatusMovedPermanently, location: "/users/Ann",
+		},
+		{
+			name: "case insensitive with PUT",
+			setup: func(r *Router) {
+				r.SetRedirectCaseInsensitive(true)
+				r.PUT("/users/:id", ok)
+			},
+			method: MethodPut, target: "/USERS/1", code: http.StatusPermanentRedirect, location: "/users/1",
+		},
+		{
+			name: "reserved characters stay escaped",
+			setup: func(r *Router) {
+				r.SetRedirectCaseInsensitive(true)
+				r.GET("/users/:id", ok)
+			},
+			method: MethodGet, target: "/Users/a%3Fb?x=1", code: http.StatusMovedPermanently, location: "/users/a%3Fb?x=1",
+		},
+		{
+			name: "escaped fragment and space",
+			setup: func(r *Router) {
+				r.SetRedirectCleanPath(true)
+				r.GET("/users/:id", ok)
+			},
+			method: MethodGet, target: "//users/a%23b%20c", code: http.StatusMovedPermanently, location: "/users/a%23b%20c",
+		},
+		{
+			name: "raw path keeps escaped slash",
+			setup: func(r *Router) {
+				r.SetUseRawPath(true)
+				r.SetRedirectCaseInsensitive(true)
+				r.GET("/keys/:key", ok)
+			},
+			method: MethodGet, target: "/Keys/a%2Fb%3F", code: http.StatusMovedPermanently, location: "/keys/a%2Fb%3F",
+		},
+		{
+			name: "exact match is not redirected",
+			setup: func(r *Router) {
+				r.SetRedirectCleanPath(true)
+				r.SetRedirectCaseInsensitive(true)
+				r.GET("/Users", ok)
+			},
+			method: MethodGet, target: "/Users", code: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRouter()
+			tt.setup(r)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+
+			if w.Code != tt.code {
+				t.Errorf("%s %s: status = %d, want %d", tt.method, tt.target, w.Code, tt.code)
+			}
+			if location := w.Header().Get("Location"); location != tt.location {
+				t.Errorf("%s %s: Location = %q, want %q", tt.method, tt.target, location, tt.location)
+			}
+		})
+	}
+}
+
+func TestRedirectMiddlewares(t *testing.T) {
+	built, ran := 0, 0
+	r := NewRouter()
+	r.Use(func(next HandlerFunc) HandlerFunc {
+		built++
+		return func(c *nexctx.Context) {
+			ran++
+			next(c)
+		}
+	})
+	r.SetRedirectCleanPath(true)
+	r.GET("/b", func(c *nexctx.Context) {})
+	built = 0
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(MethodGet, "/a/../b?x=1", nil))
+		if w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != "/b?x=1" {
+			t.Fatalf("redirect = %d to %q, want 301 to %q", w.Code, w.Header().Get("Location"), "/b?x=1")
+		}
+	}
+	if built != 0 || ran != 3 {
+		t.Errorf("middleware built %d times and ran %d times, want 0 and 3", built, ran)
+	}
+}
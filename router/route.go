@@ -0,0 +1,165 @@
+package router
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Route is a handler registered on the router for a path and one or more methods.
+type Route struct {
+	router      *Router
+	name        string
+	host        string // host pattern of the group the route belongs to
+	group       string // prefix of the group the route belongs to
+	methods     []string
+	path        string
+	variants    [][]routePart // parsed patterns, shortest first when the path has optional parts
+	handler     HandlerFunc
+	middlewares []MiddlewareFunc
+	chain       HandlerFunc // handler behind the global and route middlewares
+	maxBodySize int64       // overrides the router's limit if not 0
+	registered  bool        // false if the router rejected the route
+
+	// request matchers telling apart routes with the same method and path
+	headers  []string // canonical key/value pairs
+	queries  []string // key/value pairs
+	consumes []string
+	produces []string
+
+	// metadata reported by Router.Routes
+	tags []string
+	meta map[string]any
+}
+
+// Name names the route so its URL can be built with Router.URL. Names must
+// be unique within a router.
+func (rt *Route) Name(name string) *Route {
+	rt.name = name
+	if rt.router == nil || !rt.registered {
+		// a route that failed to register has its error reported already
+		return rt
+	}
+
+	if existing, ok := rt.router.names[name]; ok && existing != rt {
+		rt.router.addError(&RouteError{
+			Methods: rt.methods,
+			Path:    rt.path,
+			Err:     fmt.Errorf("%w: %q is already used by %s", ErrDuplicateName, name, existing.path),
+		})
+		return rt
+	}
+	rt.router.names[name] = rt
+	return rt
+}
+
+// MaxBodySize limits the size of the route's request bodies to n bytes,
+// overriding the router's limit. A negative n lifts the router's limit for
+// the route.
+//
+//	r.POST("/uploads", upload).MaxBodySize(32 << 20)
+func (rt *Route) MaxBodySize(n int64) *Route {
+	rt.maxBodySize = n
+	return rt
+}
+
+// URL builds the path of the route, filling in its params from the given
+// key/value pairs. Each value is checked against the param's constraint.
+// Optional parts are included when values for all their params are given. A
+// value for a param the URL does not use, such as an optional param whose
+// enclosing params are missing, is an error.
+func (rt *Route) URL(pairs ...string) (string, error) {
+	if len(rt.variants) == 0 {
+		return "", fmt.Errorf("route %s: not registered", rt.path)
+	}
+	if len(pairs)%2 != 0 {
+		return "", fmt.Errorf("route %s: odd number of param key/value pairs", rt.path)
+	}
+
+	values := make(map[string]string, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		values[pairs[i]] = pairs[i+1]
+	}
+
+	parts := rt.variants[0]
+	for i := len(rt.variants) - 1; i > 0; i-- {
+		if hasParams(rt.variants[i], values) {
+			parts = rt.variants[i]
+			break
+		}
+	}
+	for name, value := range values {
+		if value != "" && !hasParam(parts, name) {
+			return "", fmt.Errorf("route %s: param %q is not used by the URL", rt.path, name)
+		}
+	}
+
+	var b strings.Builder
+	for _, part := range parts {
+		if part.param == nil {
+			b.WriteString(part.pattern)
+			continue
+		}
+
+		value, ok := values[part.param.name]
+		if !ok || value == "" {
+			return "", fmt.Errorf("route %s: missing value for param %q", rt.path, part.param.name)
+		}
+		if _, matched := part.param.match(value); !matched {
+			return "", fmt.Errorf("route %s: value %q does not match param %q", rt.path, value, part.param.name)
+		}
+		b.WriteString(escapeParam(part.param, value))
+	}
+
+	if b.Len() == 0 {
+		return "/", nil
+	}
+	return b.String(), nil
+}
+
+// URL builds the path of the route registered under name, filling in its
+// params from the given key/value pairs.
+//
+//	r.GET("/users/:id(\\d+)", showUser).Name("user.show")
+//	path, err := r.URL("user.show", "id", "42") // "/users/42"
+func (r *Router) URL(name string, pairs ...string) (string, error) {
+	rt, ok := r.names[name]
+	if !ok {
+		return "", fmt.Errorf("route %q not found", name)
+	}
+	return rt.URL(pairs...)
+}
+
+// hasParams reports whether values holds a non-empty value for every param of parts.
+func hasParams(parts []routePart, values map[string]string) bool {
+	for _, part := range parts {
+		if part.param != nil && values[part.param.name] == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// hasParam reports whether parts holds a param with the given name.
+func hasParam(parts []routePart, name string) bool {
+	for _, part := range parts {
+		if part.param != nil && part.param.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeParam escapes a param value for use in a path. Catch-all values keep
+// their slashes.
+func escapeParam(pm *paramMatcher, value string) string {
+	if pm.ptype != wildcardParam {
+		return url.PathEscape(value)
+	}
+
+	segments := strings.Split(value, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,92 @@
+package router
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	nexctx "github.com/nex-gen-tech/nex/context"
+)
+
+func TestRouteURL(t *testing.T) {
+	r := NewRouter()
+	r.GET("/users/:id(\\d+)", nil).Name("user")
+	r.GET("/files/*path", nil).Name("file")
+	r.GET("/archive[/:year[/:month]]", nil).Name("archive")
+
+	tests := []struct {
+		name    string
+		pairs   []string
+		want    string
+		wantErr bool
+	}{
+		{name: "user", pairs: []string{"id", "42"}, want: "/users/42"},
+		{name: "user", pairs: []string{"id", "abc"}, wantErr: true},
+		{name: "user", pairs: nil, wantErr: true},
+		{name: "user", pairs: []string{"id"}, wantErr: true},
+		{name: "user", pairs: []string{"id", "42", "tab", "posts"}, wantErr: true},
+		{name: "file", pairs: []string{"path", "a b/c.txt"}, want: "/files/a%20b/c.txt"},
+		{name: "archive", pairs: nil, want: "/archive"},
+		{name: "archive", pairs: []string{"year", "2024"}, want: "/archive/2024"},
+		{name: "archive", pairs: []string{"year", "2024", "month", "1"}, want: "/archive/2024/1"},
+		{name: "archive", pairs: []string{"month", "1"}, wantErr: true},
+		{name: "missing", pairs: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := r.URL(tt.name, tt.pairs...)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("URL(%q, %q) = %q, want an error", tt.name, tt.pairs, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("URL(%q, %q) = %q, %v, want %q", tt.name, tt.pairs, got, err, tt.want)
+		}
+	}
+}
+
+func TestRouteURLUnregistered(t *testing.T) {
+	r := NewRouter()
+	r.GET("/u/:id", nil)
+	rt := r.GET("/u/:uid/x", nil).Name("bad")
+
+	if _, err := r.URL("bad", "uid", "1"); err == nil {
+		t.Error(`URL("bad") of a route that failed to register succeeded, want an error`)
+	}
+	if _, err := rt.URL("uid", "1"); err == nil {
+		t.Error("Route.URL of a route that failed to register succeeded, want an error")
+	}
+	if err := r.Validate(); err == nil {
+		t.Error("Validate() = nil, want the registration error")
+	}
+}
+
+func TestOptionalParamLookup(t *testing.T) {
+	type result struct {
+		value string
+		ok    bool
+	}
+	var got result
+
+	r := NewRouter()
+	r.GET("/posts/:page?", func(c *nexctx.Context) {
+		got.value, got.ok = c.PathParam.Lookup("page")
+	})
+
+	tests := []struct {
+		path string
+		want result
+	}{
+		{path: "/posts", want: result{}},
+		{path: "/posts/", want: result{}},
+		{path: "/posts/3", want: result{value: "3", ok: true}},
+	}
+	for _, tt := range tests {
+		got = result{value: "unset"}
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(MethodGet, tt.path, nil))
+		if got != tt.want {
+			t.Errorf("GET %s: Lookup(page) = %q, %v, want %q, %v", tt.path, got.value, got.ok, tt.want.value, tt.want.ok)
+		}
+	}
+}
@@ -1,29 +1,36 @@
 package router
 
 import (
-	"context"
-	"fmt"
-	"log"
+	"errors"
 	"net/http"
-	"os"
-	"os/signal"
-	"reflect"
-	"runtime"
-	"syscall"
-	"time"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	nexctx "github.com/nex-gen-tech/nex/context"
 	"github.com/nex-gen-tech/nexlog"
 )
 
 const (
-	MethodGet    = "GET"
-	MethodPost   = "POST"
-	MethodPut    = "PUT"
-	MethodDelete = "DELETE"
-	MethodPatch  = "PATCH"
+	MethodGet     = "GET"
+	MethodPost    = "POST"
+	MethodPut     = "PUT"
+	MethodDelete  = "DELETE"
+	MethodPatch   = "PATCH"
+	MethodHead    = "HEAD"
+	MethodOptions = "OPTIONS"
+	MethodConnect = "CONNECT"
+	MethodTrace   = "TRACE"
 )
 
+// anyMethods are the methods a route registered with Any responds to.
+var anyMethods = []string{
+	MethodGet, MethodPost, MethodPut, MethodDelete, MethodPatch,
+	MethodHead, MethodOptions, MethodConnect, MethodTrace,
+}
+
 // HandlerFunc defines a function to serve HTTP requests.
 type HandlerFunc func(*nexctx.Context)
 
@@ -34,20 +41,66 @@ type Router struct {
 	log          nexlog.Logger
 	errorHandler func(*nexctx.Context, error)
 	middlewares  []MiddlewareFunc
+	names        map[string]*Route
+	routes       []*Route // registered routes in registration order
+	errs         RouteErrors
+	hosts        []*hostRoute
+	scopes       []*groupScope // handlers set by groups for their prefix
+
+	// handlers for requests no route matches
+	notFound             HandlerFunc
+	methodNotAllowed     HandlerFunc
+	notAcceptable        HandlerFunc
+	unsupportedMediaType HandlerFunc
+	fallbacks            fallbackChains // fallback handlers behind the global middlewares
 
 	// for route printing
 	printRoutes      bool
 	printMiddlewares bool
+
+	// automatic responses for requests that match a path but not a method
+	handleMethodNotAllowed bool
+	handleOptions          bool
+	handleHead             bool
+
+	// redirect policies for requests that match no route
+	redirectTrailingSlash   bool
+	redirectCleanPath       bool
+	redirectCaseInsensitive bool
+
+	// match routes against the escaped request path
+	useRawPath bool
+
+	// limit on the size of request bodies, 0 for none
+	maxBodySize int64
+
+	// server lifecycle
+	mu       sync.Mutex
+	server   *serverState // nil when not serving
+	draining atomic.Bool  // set once shutdown starts
+	inFlight atomic.Int64 // requests being served
 }
 
 // NewRouter returns a new router instance.
 func NewRouter() *Router {
-	return &Router{
+	r := &Router{
 		tree:             NewTree(),
 		log:              nexlog.New("NEX-LOG"),
+		names:            make(map[string]*Route),
 		printRoutes:      false,
 		printMiddlewares: false,
+
+		notFound:             notFoundHandler,
+		methodNotAllowed:     methodNotAllowedHandler,
+		notAcceptable:        notAcceptableHandler,
+		unsupportedMediaType: unsupportedMediaTypeHandler,
+
+		handleMethodNotAllowed: true,
+		handleOptions:          true,
+		handleHead:             true,
 	}
+	r.compile()
+	return r
 }
 
 // SetErrorHandler sets a custom error handler for the router.
@@ -55,12 +108,45 @@ func (r *Router) SetErrorHandler(handler func(*nexctx.Context, error)) {
 	r.errorHandler = handler
 }
 
-// SetPrintRoutes sets the router to print the registered routes on startup.
+// NotFound sets the handler for requests no route matches. It runs behind the
+// global middlewares, so interceptors registered with Use see 404 traffic too.
+func (r *Router) NotFound(handler HandlerFunc) {
+	r.notFound = handler
+	r.compile()
+}
+
+// MethodNotAllowed sets the handler for requests whose path matches a route
+// registered under other methods. It runs behind the global middlewares and
+// the Allow header is already set on the response when it is called.
+func (r *Router) MethodNotAllowed(handler HandlerFunc) {
+	r.methodNotAllowed = handler
+	r.compile()
+}
+
+// NotAcceptable sets the handler for requests that match a path and method
+// but none of the header or Accept matchers of its routes. It runs behind
+// the global middlewares.
+func (r *Router) NotAcceptable(handler HandlerFunc) {
+	r.notAcceptable = handler
+	r.compile()
+}
+
+// UnsupportedMediaType sets the handler for requests that match a path and
+// method but whose Content-Type none of its routes consumes. It runs behind
+// the global middlewares.
+func (r *Router) UnsupportedMediaType(handler HandlerFunc) {
+	r.unsupportedMediaType = handler
+	r.compile()
+}
+
+// SetPrintRoutes sets the router to print a table of the registered routes,
+// grouped by host and group prefix, on startup.
 func (r *Router) SetPrintRoutes(print bool) {
 	r.printRoutes = print
 }
 
-// SetPrintMiddlewares sets the router to print the registered middlewares on startup.
+// SetPrintMiddlewares sets the router to list the middleware chain of each
+// route in the startup route table.
 func (r *Router) SetPrintMiddlewares(print bool) {
 	// if printRoutes is false show error message
 	if !r.printRoutes {
@@ -70,6 +156,73 @@ func (r *Router) SetPrintMiddlewares(print bool) {
 	r.printMiddlewares = print
 }
 
+// SetUseRawPath sets whether routes are matched against the escaped request
+// path instead of the decoded one. An encoded slash ("%2F") then stays inside
+// a param value instead of splitting the segment, which is needed for keys
+// that contain slashes. Param values are unescaped after matching. Static
+// text in routes is compared with the escaped path as is.
+func (r *Router) SetUseRawPath(use bool) {
+	r.useRawPath = use
+}
+
+// requestPath returns the path of the request routes are matched against.
+func (r *Router) requestPath(req *http.Request) string {
+	if r.useRawPath {
+		return req.URL.EscapedPath()
+	}
+	return req.URL.Path
+}
+
+// paramValue returns a matched param value as handlers see it, unescaped
+// when routes are matched against the raw path.
+func (r *Router) paramValue(value string) string {
+	if !r.useRawPath {
+		return value
+	}
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		return unescaped
+	}
+	return value
+}
+
+// SetMaxBodySize limits the size of request bodies to n bytes. Requests
+// declaring a larger Content-Length are answered with 413 Request Entity Too
+// Large before reaching the handler; reading past the limit fails with
+// context.ErrBodyTooLarge. Routes can set their own limit with
+// Route.MaxBodySize. Zero, the default, means no limit.
+func (r *Router) SetMaxBodySize(n int64) {
+	r.maxBodySize = n
+}
+
+// bodyLimit returns the body size limit of the route, or 0 for none.
+func (r *Router) bodyLimit(rt *Route) int64 {
+	if rt.maxBodySize != 0 {
+		return rt.maxBodySize
+	}
+	return r.maxBodySize
+}
+
+// SetHandleMethodNotAllowed sets whether a request whose path matches a route
+// registered under other methods is answered with 405 Method Not Allowed and
+// an Allow header. When disabled such requests get a 404. Enabled by default.
+func (r *Router) SetHandleMethodNotAllowed(handle bool) {
+	r.handleMethodNotAllowed = handle
+}
+
+// SetHandleOptions sets whether OPTIONS requests without an explicit OPTIONS
+// route are answered automatically with the Allow header of the path.
+// Enabled by default.
+func (r *Router) SetHandleOptions(handle bool) {
+	r.handleOptions = handle
+}
+
+// SetHandleHead sets whether HEAD requests without an explicit HEAD route are
+// served by the GET handler of the path with the response body discarded.
+// Enabled by default.
+func (r *Router) SetHandleHead(handle bool) {
+	r.handleHead = handle
+}
+
 // Group creates a new router group with the specified prefix.
 func (r *Router) Group(prefix string) *RouterGroup {
 	return &RouterGroup{
@@ -79,120 +232,305 @@ func (r *Router) Group(prefix string) *RouterGroup {
 	}
 }
 
-// Use appends middleware(s) to the router's middleware stack.
+// Use appends middleware(s) to the router's middleware stack. They apply to
+// every route, including the ones registered before.
 func (r *Router) Use(middleware ...MiddlewareFunc) {
-	r.middlewares = append(r.middlewares, middleware...)
+	r.middlewares = append(r.middlewares[:len(r.middlewares):len(r.middlewares)], middleware...)
+	r.compile()
 }
 
-func (r *Router) AddRoute(method, path string, handler HandlerFunc, middlewares ...MiddlewareFunc) {
-	r.tree.AddRoute(method, method+":"+path, handler, middlewares...)
+// AddRoute registers the handler for the method and path and returns the
+// route so it can be configured further, for example named.
+func (r *Router) AddRoute(method, path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return r.addRoute(r.tree, "", "", []string{method}, path, handler, middlewares)
 }
 
-// ServeHTTP implements the http.Handler interface.
-func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	ctx := nexctx.NewContext(w, req)
-	handler, params, nodeMiddlewares := r.tree.Match(req.Method + ":" + req.URL.Path)
+// addRoute registers one route in the tree for the path under each of the
+// given methods.
+func (r *Router) addRoute(tree *Tree, host, group string, methods []string, path string, handler HandlerFunc, middlewares []MiddlewareFunc) *Route {
+	rt := &Route{
+		router:      r,
+		host:        host,
+		group:       group,
+		methods:     methods,
+		path:        path,
+		handler:     handler,
+		middlewares: middlewares,
+	}
+	if tree.detached {
+		// the invalid host was reported already
+		return rt
+	}
+	if err := tree.add(rt); err != nil {
+		r.addError(err)
+		return rt
+	}
+	rt.registered = true
+	rt.chain = compose(handler, r.middlewares, middlewares)
+	r.routes = append(r.routes, rt)
+	return rt
+}
+
+// addError records a registration error so it can be reported by Validate.
+func (r *Router) addError(err *RouteError) {
+	r.log.Error(err.Error())
+	r.errs = append(r.errs, err)
+}
+
+// Validate reports the errors collected while registering routes, such as
+// duplicate routes, conflicting param names or invalid patterns. Routes that
+// failed to register are not served. Run refuses to start while Validate
+// returns an error.
+func (r *Router) Validate() error {
+	errs := append(RouteErrors(nil), r.errs...)
+	errs = append(errs, r.tree.duplicates()...)
+	for _, h := range r.hosts {
+		errs = append(errs, h.tree.duplicates()...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
 
-	if handler == nil {
-		http.NotFound(w, req)
+// ServeHTTP implements the http.Handler interface. The Context passed to
+// handlers comes from a pool and is recycled once the request is served, so
+// handlers must not keep it.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.draining.Load() {
+		w.Header().Set("Connection", "close")
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
 		return
 	}
+	r.inFlight.Add(1)
+	defer r.inFlight.Add(-1)
+
+	params := paramsPool.Get().(*[]paramValue)
+	defer releaseParams(params)
 
-	for key, value := range params {
-		ctx.Params[key] = value
+	reqPath := r.requestPath(req)
+	tree := r.treeFor(req.Host, params)
+	hostParams := len(*params)
+	routes := tree.find(req.Method, reqPath, params)
+
+	if routes == nil && req.Method == MethodHead && r.handleHead {
+		routes = tree.find(MethodGet, reqPath, params)
+		if routes != nil {
+			w = &headResponseWriter{ResponseWriter: w}
+		}
 	}
 
-	allMiddlewares := append(r.middlewares, nodeMiddlewares...)
-	// for _, middleware := range allMiddlewares {
-	// 	name := runtime.FuncForPC(reflect.ValueOf(middleware).Pointer()).Name()
-	// 	log.Printf("middleware: %s", name)
-	// 	handler = middleware(handler)
-	// }
-	// reverse the middleware stack
-	for i := len(allMiddlewares) - 1; i >= 0; i-- {
-		name := runtime.FuncForPC(reflect.ValueOf(allMiddlewares[i]).Pointer()).Name()
-		log.Printf("middleware: %s", name)
-		handler = allMiddlewares[i](handler)
+	var handler HandlerFunc
+	if routes == nil {
+		handler = r.unmatchedHandler(w, req, tree, reqPath)
+	} else if rt, status := selectRoute(routes, req); rt != nil {
+		handler = rt.chain
+		if limit := r.bodyLimit(rt); limit > 0 {
+			if req.ContentLength > limit {
+				handler = r.fallbacks.payloadTooLarge
+			} else if req.Body != nil && req.Body != http.NoBody {
+				req.Body = http.MaxBytesReader(w, req.Body, limit)
+			}
+		}
+	} else {
+		handler = r.rejectedHandler(tree, reqPath, status)
+	}
+
+	ctx := nexctx.AcquireContext(w, req)
+	defer nexctx.ReleaseContext(ctx)
+
+	for i, p := range *params {
+		value := p.value
+		if i >= hostParams {
+			value = r.paramValue(value)
+		}
+		ctx.Params = append(ctx.Params, nexctx.Param{Key: p.name, Value: value})
+		if p.typed != nil {
+			ctx.SetParamValue(p.name, p.typed)
+		}
 	}
 
 	// Execute the handler
 	handler(ctx)
 
 	// Check if an error was set in the context
-	if ctx.Error() != nil && r.errorHandler != nil {
-		r.errorHandler(ctx, ctx.Error())
+	if err := ctx.Error(); err != nil {
+		if errorHandler := r.errorHandlerFor(tree, reqPath); errorHandler != nil {
+			errorHandler(ctx, err)
+		} else if errors.Is(err, nexctx.ErrBodyTooLarge) {
+			http.Error(ctx.Response, err.Error(), http.StatusRequestEntityTooLarge)
+		}
+	}
+}
+
+// paramsPool recycles the buffers params are captured in while matching.
+var paramsPool = sync.Pool{
+	New: func() any {
+		params := make([]paramValue, 0, 8)
+		return &params
+	},
+}
+
+// releaseParams clears the captured params and returns the buffer to the pool.
+func releaseParams(params *[]paramValue) {
+	for i := range *params {
+		(*params)[i] = paramValue{}
+	}
+	*params = (*params)[:0]
+	paramsPool.Put(params)
+}
+
+// unmatchedHandler returns the handler for a request no route handles. It
+// redirects to the canonical path if a redirect policy applies. If the path
+// is registered under other methods it answers OPTIONS automatically or
+// replies with 405, otherwise with 404. The returned handler runs behind the
+// global middlewares like any other route.
+func (r *Router) unmatchedHandler(w http.ResponseWriter, req *http.Request, tree *Tree, reqPath string) HandlerFunc {
+	if target := r.redirectTarget(tree, req.Method, reqPath); target != "" {
+		if req.URL.RawQuery != "" {
+			target += "?" + req.URL.RawQuery
+		}
+		w.Header().Set("Location", target)
+		return r.fallbacks.redirect
+	}
+
+	if allowed := r.allowed(tree, reqPath); len(allowed) > 0 {
+		if req.Method == MethodOptions && r.handleOptions {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			return r.fallbacks.options
+		}
+		if r.handleMethodNotAllowed {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			return r.fallbacks.methodNotAllowed
+		}
+	}
+
+	return r.notFoundFor(tree, reqPath)
+}
+
+// rejectedHandler returns the handler for a request whose path and method
+// are registered but whose headers, query or body match none of the routes.
+func (r *Router) rejectedHandler(tree *Tree, reqPath string, status int) HandlerFunc {
+	switch status {
+	case http.StatusUnsupportedMediaType:
+		return r.fallbacks.unsupportedMediaType
+	case http.StatusNotAcceptable:
+		return r.fallbacks.notAcceptable
 	}
+	return r.notFoundFor(tree, reqPath)
+}
+
+// allowed returns the methods the path can be requested with, including the
+// ones the router answers automatically.
+func (r *Router) allowed(tree *Tree, path string) []string {
+	allowed := tree.Allowed(path)
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	hasMethod := func(method string) bool {
+		for _, m := range allowed {
+			if m == method {
+				return true
+			}
+		}
+		return false
+	}
+	if r.handleHead && hasMethod(MethodGet) && !hasMethod(MethodHead) {
+		allowed = append(allowed, MethodHead)
+	}
+	if r.handleOptions && !hasMethod(MethodOptions) {
+		allowed = append(allowed, MethodOptions)
+	}
+	sort.Strings(allowed)
+	return allowed
+}
+
+// notFoundHandler is the default handler for requests no route matches.
+func notFoundHandler(c *nexctx.Context) {
+	http.NotFound(c.Response, c.Request)
+}
+
+// methodNotAllowedHandler is the default handler for requests whose path
+// matches a route registered under other methods.
+func methodNotAllowedHandler(c *nexctx.Context) {
+	http.Error(c.Response, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
+
+// payloadTooLargeHandler answers requests whose declared body size exceeds
+// the limit of the route.
+func payloadTooLargeHandler(c *nexctx.Context) {
+	http.Error(c.Response, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+}
+
+// optionsHandler answers OPTIONS requests for paths without an OPTIONS route.
+// The Allow header is already set by the router.
+func optionsHandler(c *nexctx.Context) {
+	c.Response.WriteHeader(http.StatusNoContent)
+}
+
+// headResponseWriter discards the body written by a GET handler serving a HEAD request.
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+// Write discards b and reports it as written.
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
 }
 
 // GET is a shortcut for router.AddRoute("GET", path, handler)
-func (r *Router) GET(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) {
-	r.AddRoute(MethodGet, path, handler, middlewares...)
+func (r *Router) GET(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return r.AddRoute(MethodGet, path, handler, middlewares...)
 }
 
 // POST is a shortcut for router.AddRoute("POST", path, handler)
-func (r *Router) POST(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) {
-	r.AddRoute(MethodPost, path, handler, middlewares...)
+func (r *Router) POST(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return r.AddRoute(MethodPost, path, handler, middlewares...)
 }
 
 // PUT is a shortcut for router.AddRoute("PUT", path, handler)
-func (r *Router) PUT(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) {
-	r.AddRoute(MethodPut, path, handler, middlewares...)
+func (r *Router) PUT(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return r.AddRoute(MethodPut, path, handler, middlewares...)
 }
 
 // DELETE is a shortcut for router.AddRoute("DELETE", path, handler)
-func (r *Router) DELETE(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) {
-	r.AddRoute(MethodDelete, path, handler, middlewares...)
+func (r *Router) DELETE(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return r.AddRoute(MethodDelete, path, handler, middlewares...)
 }
 
 // PATCH is a shortcut for router.AddRoute("PATCH", path, handler)
-func (r *Router) PATCH(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) {
-	r.AddRoute(MethodPatch, path, handler, middlewares...)
+func (r *Router) PATCH(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return r.AddRoute(MethodPatch, path, handler, middlewares...)
 }
 
-// Run starts the HTTP server.
-func (r *Router) Run(addr string) error {
-	// Print the list of registered routes
-	r.tree.root.printRoutes(printRoutesConfig{Prefix: ""})
-
-	r.Address = addr
+// HEAD is a shortcut for router.AddRoute("HEAD", path, handler)
+func (r *Router) HEAD(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return r.AddRoute(MethodHead, path, handler, middlewares...)
+}
 
-	// Define the server
-	s := &http.Server{
-		Addr:    r.Address,
-		Handler: r,
-	}
+// OPTIONS is a shortcut for router.AddRoute("OPTIONS", path, handler)
+func (r *Router) OPTIONS(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return r.AddRoute(MethodOptions, path, handler, middlewares...)
+}
 
-	errCh := make(chan error, 1)
+// CONNECT is a shortcut for router.AddRoute("CONNECT", path, handler)
+func (r *Router) CONNECT(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return r.AddRoute(MethodConnect, path, handler, middlewares...)
+}
 
-	// Start the server in a goroutine so that it doesn't block
-	go func() {
-		if err := s.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			errCh <- fmt.Errorf("error starting server: %s", err.Error())
-		}
-		close(errCh)
-	}()
-
-	// Wait for interrupt signal to gracefully shutdown the server with
-	// a timeout of 5 seconds.
-	quit := make(chan os.Signal, 1)
-	// kill (no param) default send syscall.SIGTERM
-	// kill -2 is syscall.SIGINT
-	// kill -9 is syscall.SIGKILL but can't be caught, so don't need to add it
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	select {
-	case <-quit:
-		// The context is used to inform the server it has 5 seconds to finish
-		// the request it is currently handling
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-
-		if err := s.Shutdown(ctx); err != nil {
-			return fmt.Errorf("server forced to shutdown: %s", err.Error())
-		}
+// TRACE is a shortcut for router.AddRoute("TRACE", path, handler)
+func (r *Router) TRACE(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return r.AddRoute(MethodTrace, path, handler, middlewares...)
+}
 
-		return nil
+// Any registers the handler for the path under every HTTP method.
+func (r *Router) Any(path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return r.Match(anyMethods, path, handler, middlewares...)
+}
 
-	case err := <-errCh:
-		return err
-	}
+// Match registers the handler for the path under each of the given methods.
+func (r *Router) Match(methods []string, path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return r.addRoute(r.tree, "", "", methods, path, handler, middlewares)
 }
@@ -0,0 +1,221 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	nexctx "github.com/nex-gen-tech/nex/context"
+)
+
+func TestAutomaticMethods(t *testing.T) {
+	body := func(text string) HandlerFunc {
+		return func(c *nexctx.Context) { c.Response.Write([]byte(text)) }
+	}
+
+	tests := []struct {
+		name   string
+		setup  func(r *Router)
+		method string
+		path   string
+		code   int
+		allow  string
+		body   string
+	}{
+		{
+			name: "allow lists methods served by other routes",
+			setup: func(r *Router) {
+				r.GET("/users/me", body("me"))
+				r.DELETE("/users/:id", body("deleted"))
+			},
+			method: MethodPut, path: "/users/me",
+			code: http.StatusMethodNotAllowed, allow: "DELETE, GET, HEAD, OPTIONS",
+		},
+		{
+			name: "method served by a param route",
+			setup: func(r *Router) {
+				r.GET("/users/me", body("me"))
+				r.DELETE("/users/:id", body("deleted"))
+			},
+			method: MethodDelete, path: "/users/me",
+			code: http.StatusOK, body: "deleted",
+		},
+		{
+			name: "options preflight",
+			setup: func(r *Router) {
+				r.GET("/users/me", body("me"))
+				r.DELETE("/users/:id", body("deleted"))
+			},
+			method: MethodOptions, path: "/users/me",
+			code: http.StatusNoContent, allow: "DELETE, GET, HEAD, OPTIONS",
+		},
+		{
+			name: "allow includes catch-all routes",
+			setup: func(r *Router) {
+				r.GET("/files/:name", body("file"))
+				r.POST("/files/*path", body("upload"))
+			},
+			method: MethodPut, path: "/files/a.txt",
+			code: http.StatusMethodNotAllowed, allow: "GET, HEAD, OPTIONS, POST",
+		},
+		{
+			name:   "405 disabled",
+			setup:  func(r *Router) { r.SetHandleMethodNotAllowed(false); r.GET("/a", body("a")) },
+			method: MethodPost, path: "/a",
+			code: http.StatusNotFound,
+		},
+		{
+			name:   "options disabled",
+			setup:  func(r *Router) { r.SetHandleOptions(false); r.GET("/a", body("a")) },
+			method: MethodOptions, path: "/a",
+			code: http.StatusMethodNotAllowed, allow: "GET, HEAD",
+		},
+		{
+			name:   "explicit options route",
+			setup:  func(r *Router) { r.GET("/a", body("a")); r.OPTIONS("/a", body("custom")) },
+			method: MethodOptions, path: "/a",
+			code: http.StatusOK, body: "custom",
+		},
+		{
+			name:   "head served by get without body",
+			setup:  func(r *Router) { r.GET("/a", body("a")) },
+			method: MethodHead, path: "/a",
+			code: http.StatusOK,
+		},
+		{
+			name:   "head disabled",
+			setup:  func(r *Router) { r.SetHandleHead(false); r.GET("/a", body("a")) },
+			method: MethodHead, path: "/a",
+			code: http.StatusMethodNotAllowed, allow: "GET, OPTIONS",
+		},
+		{
+			name:   "unknown path",
+			setup:  func(r *Router) { r.GET("/a", body("a")) },
+			method: MethodPut, path: "/b",
+			code: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRouter()
+			tt.setup(r)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.code {
+				t.Errorf("%s %s: status = %d, want %d", tt.method, tt.path, w.Code, tt.code)
+			}
+			if allow := w.Header().Get("Allow"); allow != tt.allow {
+				t.Errorf("%s %s: Allow = %q, want %q", tt.method, tt.path, allow, tt.allow)
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("%s %s: body = %q, want %q", tt.method, tt.path, w.Body.String(), tt.body)
+			}
+			if tt.method == MethodHead && w.Code == http.StatusOK && w.Body.Len() != 0 {
+				t.Errorf("HEAD %s: body = %q, want none", tt.path, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestUseRawPath(t *testing.T) {
+	echo := func(name string) HandlerFunc {
+		return func(c *nexctx.Context) { c.Response.Write([]byte(c.Params.Get(name))) }
+	}
+
+	tests := []struct {
+		raw  bool
+		path string
+		code int
+		body string
+	}{
+		{raw: true, path: "/keys/a%2Fb", code: http.StatusOK, body: "a/b"},
+		{raw: true, path: "/keys/a%20b%3F", code: http.StatusOK, body: "a b?"},
+		{raw: true, path: "/files/a%2Fb/c%20d", code: http.StatusOK, body: "a/b/c d"},
+		{raw: true, path: "/hello%20world", code: http.StatusOK, body: ""},
+		{raw: false, path: "/keys/a%2Fb", code: http.StatusNotFound},
+		{raw: false, path: "/keys/a%20b", code: http.StatusOK, body: "a b"},
+		{raw: false, path: "/files/a%2Fb/c", code: http.StatusOK, body: "a/b/c"},
+	}
+
+	for _, tt := range tests {
+		r := NewRouter()
+		r.SetUseRawPath(tt.raw)
+		r.GET("/keys/:key", echo("key"))
+		r.GET("/files/*path", echo("path"))
+		r.GET("/hello%20world", echo("none"))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(MethodGet, tt.path, nil))
+		if w.Code != tt.code {
+			t.Errorf("raw=%v GET %s: status = %d, want %d", tt.raw, tt.path, w.Code, tt.code)
+			continue
+		}
+		if tt.code == http.StatusOK && w.Body.String() != tt.body {
+			t.Errorf("raw=%v GET %s: body = %q, want %q", tt.raw, tt.path, w.Body.String(), tt.body)
+		}
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	parse := func(c *nexctx.Context) {
+		var v map[string]string
+		if err := c.Body.ParseJSON(&v); err != nil {
+			c.SetError(err)
+			return
+		}
+		c.Response.WriteHeader(http.StatusNoContent)
+	}
+
+	r := NewRouter()
+	r.SetMaxBodySize(16)
+	r.POST("/small", parse)
+	r.POST("/large", parse).MaxBodySize(1 << 10)
+	r.POST("/unlimited", parse).MaxBodySize(-1)
+
+	big := `{"name":"` + strings.Repeat("x", 64) + `"}`
+	tests := []struct {
+		name    string
+		path    string
+		body    string
+		chunked bool
+		code    int
+	}{
+		{name: "within limit", path: "/small", body: `{"a":"b"}`, code: http.StatusNoContent},
+		{name: "declared length over limit", path: "/small", body: big, code: http.StatusRequestEntityTooLarge},
+		{name: "chunked body over limit", path: "/small", body: big, chunked: true, code: http.StatusRequestEntityTooLarge},
+		{name: "route limit overrides router", path: "/large", body: big, code: http.StatusNoContent},
+		{name: "route lifts the limit", path: "/unlimited", body: big, chunked: true, code: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(MethodPost, tt.path, strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.code {
+				t.Errorf("POST %s: status = %d, want %d", tt.path, w.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestMaxBodySizeSkipsHandler(t *testing.T) {
+	called := false
+	r := NewRouter()
+	r.SetMaxBodySize(4)
+	r.POST("/", func(c *nexctx.Context) { called = true })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(MethodPost, "/", strings.NewReader("too large")))
+	if w.Code != http.StatusRequestEntityTooLarge || called {
+		t.Errorf("status = %d, handler called = %v, want 413 without calling the handler", w.Code, called)
+	}
+}
@@ -0,0 +1,117 @@
+package router
+
+import (
+	"strings"
+
+	nexctx "github.com/nex-gen-tech/nex/context"
+)
+
+// groupScope holds the handlers a group sets for the requests under its
+// prefix.
+type groupScope struct {
+	tree          *Tree
+	prefix        string
+	errorHandler  func(*nexctx.Context, error)
+	notFound      HandlerFunc
+	notFoundChain HandlerFunc // notFound behind the global middlewares
+	matcher       *Tree       // matches the prefix and the paths below it if it has params
+}
+
+// SetErrorHandler sets the error handler for requests under the group's
+// prefix, whether or not the route serving them was added to the group. It
+// takes precedence over the router's error handler and over the ones of
+// groups with a shorter prefix. A prefix with params, such as "/users/:id",
+// is matched like a route pattern.
+func (group *RouterGroup) SetErrorHandler(handler func(*nexctx.Context, error)) {
+	group.scope().errorHandler = handler
+}
+
+// NotFound sets the handler for requests under the group's prefix that no
+// route matches. It takes precedence over the router's NotFound handler and
+// over the ones of groups with a shorter prefix, and runs behind the global
+// middlewares. A prefix with params, such as "/users/:id", is matched like a
+// route pattern.
+//
+//	api := r.Group("/api")
+//	api.NotFound(func(c *nex.Context) {
+//		c.Res.JsonNotFound404("route not found")
+//	})
+func (group *RouterGroup) NotFound(handler HandlerFunc) {
+	group.scope().notFound = handler
+	group.router.compile()
+}
+
+// scope returns the scope of the group's prefix, creating it if needed.
+func (group *RouterGroup) scope() *groupScope {
+	r := group.router
+	tree := group.tree
+	if tree == nil {
+		tree = r.tree
+	}
+	prefix := strings.TrimSuffix(group.prefix, "/")
+
+	for _, s := range r.scopes {
+		if s.tree == tree && s.prefix == prefix {
+			return s
+		}
+	}
+	s := &groupScope{tree: tree, prefix: prefix}
+	if strings.ContainsAny(prefix, ":*[") {
+		s.matcher = NewTree()
+		s.matcher.paramTypes = tree.paramTypes
+		// an invalid prefix is reported by the group's routes; the scope
+		// then falls back to matching it literally
+		if _, err := s.matcher.AddRoute(MethodGet, prefix, nil); err != nil {
+			s.matcher = nil
+		} else if !strings.Contains(prefix, "/*") {
+			s.matcher.AddRoute(MethodGet, prefix+"/*", nil)
+		}
+	}
+	r.scopes = append(r.scopes, s)
+	return s
+}
+
+// contains reports whether the request path is under the scope's prefix.
+func (s *groupScope) contains(path string) bool {
+	if s.matcher != nil {
+		routes, _ := s.matcher.lookup(MethodGet, path)
+		return routes != nil
+	}
+	return underPrefix(path, s.prefix)
+}
+
+// underPrefix reports whether p equals prefix or continues it with a new
+// segment.
+func underPrefix(p, prefix string) bool {
+	return strings.HasPrefix(p, prefix) && (len(p) == len(prefix) || p[len(prefix)] == '/')
+}
+
+// notFoundFor returns the NotFound handler for a path of the tree: the one
+// of the group with the longest prefix containing the path, or the router's.
+func (r *Router) notFoundFor(tree *Tree, path string) HandlerFunc {
+	var best *groupScope
+	for _, s := range r.scopes {
+		if s.notFound != nil && s.tree == tree && s.contains(path) && (best == nil || len(s.prefix) > len(best.prefix)) {
+			best = s
+		}
+	}
+	if best != nil {
+		return best.notFoundChain
+	}
+	return r.fallbacks.notFound
+}
+
+// errorHandlerFor returns the error handler for a path of the tree: the one
+// of the group with the longest prefix containing the path, or the router's.
+func (r *Router) errorHandlerFor(tree *Tree, path string) func(*nexctx.Context, error) {
+	var best *groupScope
+	for _, s := range r.scopes {
+		if s.errorHandler != nil && s.tree == tree && s.contains(path) && (best == nil || len(s.prefix) > len(best.prefix)) {
+			best = s
+		}
+	}
+	if best != nil {
+		return best.errorHandler
+	}
+	return r.errorHandler
+}
@@ -0,0 +1,55 @@
+package router
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	nexctx "github.com/nex-gen-tech/nex/context"
+)
+
+func TestGroupScopes(t *testing.T) {
+	fail := func(c *nexctx.Context) { c.SetError(errors.New("boom")) }
+	reply := func(code int) func(*nexctx.Context, error) {
+		return func(c *nexctx.Context, err error) { c.Response.WriteHeader(code) }
+	}
+
+	r := NewRouter()
+	r.SetErrorHandler(reply(http.StatusInternalServerError))
+	r.GET("/users/:id/direct", fail)
+	r.GET("/other", fail)
+
+	users := r.Group("/users/:id")
+	users.SetErrorHandler(reply(http.StatusTeapot))
+	users.NotFound(func(c *nexctx.Context) { c.Response.WriteHeader(http.StatusGone) })
+	users.GET("/posts", fail)
+	users.Group("/tags").GET("/:tag", fail)
+
+	api := r.Group("/api")
+	api.SetErrorHandler(reply(http.StatusBadGateway))
+	api.GET("/items", fail)
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{path: "/users/42/posts", code: http.StatusTeapot},
+		{path: "/users/42/tags/go", code: http.StatusTeapot},
+		{path: "/users/42/direct", code: http.StatusTeapot},
+		{path: "/other", code: http.StatusInternalServerError},
+		{path: "/users/42/missing", code: http.StatusGone},
+		{path: "/users/42", code: http.StatusGone},
+		{path: "/users", code: http.StatusNotFound},
+		{path: "/api/items", code: http.StatusBadGateway},
+		{path: "/api/missing", code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(MethodGet, tt.path, nil))
+		if w.Code != tt.code {
+			t.Errorf("GET %s: status = %d, want %d", tt.path, w.Code, tt.code)
+		}
+	}
+}
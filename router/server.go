@@ -0,0 +1,296 @@
+package router
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// defaultShutdownTimeout is how long in-flight requests are given to finish
+// when the server shuts down, unless ServerConfig sets otherwise.
+const defaultShutdownTimeout = 5 * time.Second
+
+// drainPollInterval is how often Shutdown checks whether requests the
+// transport does not track are still in flight.
+const drainPollInterval = 10 * time.Millisecond
+
+// ShutdownError is returned by Shutdown when the drain deadline passed before
+// all in-flight requests finished. Those requests were cut off.
+type ShutdownError struct {
+	CutOff int64 // requests still in flight when the deadline passed
+	Err    error
+}
+
+// Error implements the error interface.
+func (e *ShutdownError) Error() string {
+	return fmt.Sprintf("server forced to shutdown: %d request(s) cut off: %v", e.CutOff, e.Err)
+}
+
+// Unwrap returns the underlying error, usually context.DeadlineExceeded.
+func (e *ShutdownError) Unwrap() error {
+	return e.Err
+}
+
+// serverState is the server a router is currently serving with.
+type serverState struct {
+	server Transport
+	once   sync.Once
+	done   chan struct{} // closed once the server has shut down
+	err    error         // result of the shutdown
+}
+
+// ServerConfig configures the HTTP server started by Serve, Start and
+// RunWithConfig.
+// Zero values leave the corresponding net/http defaults in place.
+type ServerConfig struct {
+	// Addr is the address to listen on, ":http" or ":https" if empty. With
+	// Network "unix" it is the path of the socket.
+	Addr    string
+	Network string // "tcp" if empty
+
+	// Listener, when set, is served instead of listening on Addr.
+	Listener net.Listener
+
+	// Transport, when set, replaces the built-in server, for example to
+	// serve HTTP/3 over QUIC. Addr is then only reported, and the listener,
+	// timeout, TLS and HTTP/2 settings are left to the transport.
+	Transport Transport
+
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+
+	// TLS is enabled when CertFile and KeyFile or TLSConfig are set. The
+	// files may be left empty when TLSConfig holds the certificates.
+	CertFile  string
+	KeyFile   string
+	TLSConfig *tls.Config
+
+	// H2C serves HTTP/2 over cleartext, with prior knowledge or by upgrade,
+	// when TLS is off. HTTP/2 is always offered over TLS.
+	H2C bool
+	// HTTP2 tunes HTTP/2 over TLS and h2c.
+	HTTP2 *HTTP2Config
+
+	// ShutdownTimeout is how long in-flight requests are given to finish
+	// when the server shuts down, 5 seconds if zero.
+	ShutdownTimeout time.Duration
+
+	// OnStartup hooks run in order once the server listens and before it
+	// accepts requests. An error stops the server from starting.
+	OnStartup []func() error
+	// OnShutdown hooks run in order once the server has stopped and drained,
+	// with a fresh context bounded by ShutdownTimeout. Errors are logged.
+	OnShutdown []func(context.Context) error
+}
+
+// Run starts the HTTP server on addr and serves until it receives SIGINT or
+// SIGTERM, then drains in-flight requests.
+func (r *Router) Run(addr string) error {
+	return r.RunWithConfig(ServerConfig{Addr: addr})
+}
+
+// RunTLS is like Run but serves HTTPS with the given certificate and key
+// files.
+func (r *Router) RunTLS(addr, certFile, keyFile string) error {
+	return r.RunWithConfig(ServerConfig{Addr: addr, CertFile: certFile, KeyFile: keyFile})
+}
+
+// RunWithConfig starts the server described by cfg and serves until it
+// receives SIGINT or SIGTERM, then drains in-flight requests.
+//
+//	err := r.RunWithConfig(router.ServerConfig{
+//		Addr:              ":8080",
+//		ReadHeaderTimeout: 5 * time.Second,
+//		IdleTimeout:       time.Minute,
+//		OnShutdown:        []func(context.Context) error{db.Close},
+//	})
+func (r *Router) RunWithConfig(cfg ServerConfig) error {
+	// kill (no param) default send syscall.SIGTERM
+	// kill -2 is syscall.SIGINT
+	// kill -9 is syscall.SIGKILL but can't be caught, so don't need to add it
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	return r.Start(ctx, cfg)
+}
+
+// Start starts the server described by cfg and serves until ctx is
+// cancelled, then shuts down like Shutdown with a deadline of
+// cfg.ShutdownTimeout. It installs no signal handler, which leaves process
+// supervision to the caller and makes the server easy to stop in tests.
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	go r.Start(ctx, router.ServerConfig{Addr: ":8080"})
+//	// ...
+//	cancel()
+func (r *Router) Start(ctx context.Context, cfg ServerConfig) error {
+	return r.serve(cfg, ctx.Done())
+}
+
+// Serve starts the server described by cfg and serves until it fails or
+// Shutdown is called. Unlike RunWithConfig it leaves signal handling to the
+// caller.
+func (r *Router) Serve(cfg ServerConfig) error {
+	return r.serve(cfg, nil)
+}
+
+// Shutdown gracefully stops the running server. It stops accepting
+// connections, answers requests that still arrive on open connections with
+// 503 Service Unavailable and waits for in-flight requests to finish,
+// including ones the transport does not track, such as h2c requests. If ctx
+// expires first, the remaining connections are closed and a *ShutdownError
+// reports how many requests were cut off. Shutdown does nothing if the router
+// is not serving.
+func (r *Router) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	st := r.server
+	r.mu.Unlock()
+	if st == nil {
+		return nil
+	}
+
+	st.once.Do(func() {
+		st.err = r.drain(ctx, st.server)
+		close(st.done)
+	})
+	<-st.done
+	return st.err
+}
+
+// drain shuts the server down, cutting off the requests still in flight when
+// ctx expires.
+func (r *Router) drain(ctx context.Context, s Transport) error {
+	r.draining.Store(true)
+	err := s.Shutdown(ctx)
+	if err == nil {
+		// hijacked connections, such as h2c ones, and custom transports may
+		// still be serving requests
+		err = r.waitIdle(ctx)
+	}
+	if err == nil {
+		return nil
+	}
+
+	cutOff := r.inFlight.Load()
+	s.Close()
+	return &ShutdownError{CutOff: cutOff, Err: err}
+}
+
+// waitIdle waits until no request is in flight or ctx expires.
+func (r *Router) waitIdle(ctx context.Context) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for r.inFlight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// serve runs the server described by cfg until it fails, Shutdown is called
+// or stop is closed, in which case in-flight requests are drained.
+func (r *Router) serve(cfg ServerConfig, stop <-chan struct{}) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+
+	s := cfg.Transport
+	r.Address = cfg.Addr
+	if s == nil {
+		t, err := newHTTPTransport(cfg)
+		if err != nil {
+			return fmt.Errorf("error starting server: %w", err)
+		}
+		s = t
+		r.Address = t.server.Addr
+	}
+
+	st := &serverState{server: s, done: make(chan struct{})}
+	r.mu.Lock()
+	r.server = st
+	r.mu.Unlock()
+	r.draining.Store(false)
+	defer func() {
+		r.mu.Lock()
+		if r.server == st {
+			r.server = nil
+		}
+		r.mu.Unlock()
+	}()
+
+	for _, hook := range cfg.OnStartup {
+		if err := hook(); err != nil {
+			s.Close()
+			return fmt.Errorf("error starting server: %w", err)
+		}
+	}
+
+	if r.printRoutes {
+		r.printRouteTable(color.Output)
+	}
+
+	errCh := make(chan error, 1)
+
+	// Start the server in a goroutine so that it doesn't block
+	go func() {
+		if err := s.Serve(r); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- fmt.Errorf("error starting server: %w", err)
+		}
+		close(errCh)
+	}()
+
+	timeout := cfg.ShutdownTimeout
+	if timeout == 0 {
+		timeout = defaultShutdownTimeout
+	}
+
+	select {
+	case <-stop:
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		err := r.Shutdown(ctx)
+		<-errCh
+		r.runShutdownHooks(timeout, cfg.OnShutdown)
+		return err
+
+	case err := <-errCh:
+		if err == nil {
+			// stopped by Shutdown, which reports its own result
+			<-st.done
+		}
+
+		r.runShutdownHooks(timeout, cfg.OnShutdown)
+		return err
+	}
+}
+
+// runShutdownHooks runs the hooks in order, logging their errors. They share
+// a context of their own bounded by timeout, so time spent draining requests
+// does not eat into it.
+func (r *Router) runShutdownHooks(timeout time.Duration, hooks []func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for _, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			r.log.ErrorF("shutdown hook: %v", err)
+		}
+	}
+}
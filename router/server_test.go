@@ -0,0 +1,242 @@
+package router
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	nexctx "github.com/nex-gen-tech/nex/context"
+	"golang.org/x/net/http2"
+)
+
+func TestShutdownHooksGetFreshContext(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := make(chan struct{})
+	r := NewRouter()
+	r.GET("/slow", func(c *nexctx.Context) {
+		close(started)
+		time.Sleep(time.Second)
+	})
+
+	hookErr := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- r.Start(ctx, ServerConfig{
+			Listener:        ln,
+			ShutdownTimeout: 200 * time.Millisecond,
+			OnShutdown: []func(context.Context) error{func(ctx context.Context) error {
+				hookErr <- ctx.Err()
+				return nil
+			}},
+		})
+	}()
+
+	go http.Get("http://" + ln.Addr().String() + "/slow")
+	<-started
+	cancel()
+
+	var shutdownErr *ShutdownError
+	if err := <-done; !errors.As(err, &shutdownErr) {
+		t.Errorf("Start() error = %v, want a *ShutdownError", err)
+	}
+	if err := <-hookErr; err != nil {
+		t.Errorf("shutdown hook context error = %v, want nil", err)
+	}
+}
+
+// serveTest serves r on a local listener in the background. It returns the
+// server's base URL and a channel receiving the result of Serve.
+func serveTest(t *testing.T, r *Router, cfg ServerConfig) (string, <-chan error) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Listener = ln
+
+	done := make(chan error, 1)
+	go func() { done <- r.Serve(cfg) }()
+	return "http://" + ln.Addr().String(), done
+}
+
+// blockingRouter returns a router whose /slow handler signals started and
+// then waits for release.
+func blockingRouter(started chan<- struct{}, release <-chan struct{}) *Router {
+	r := NewRouter()
+	r.GET("/slow", func(c *nexctx.Context) {
+		started <- struct{}{}
+		<-release
+		c.Response.WriteHeader(http.StatusNoContent)
+	})
+	return r
+}
+
+func TestShutdownDrains(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	r := blockingRouter(started, release)
+	url, done := serveTest(t, r, ServerConfig{})
+
+	status := make(chan int, 1)
+	go func() {
+		resp, err := http.Get(url + "/slow")
+		if err != nil {
+			status <- 0
+			return
+		}
+		resp.Body.Close()
+		status <- resp.StatusCode
+	}()
+	<-started
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- r.Shutdown(context.Background()) }()
+	for !r.draining.Load() {
+		time.Sleep(time.Millisecond)
+	}
+
+	// requests still arriving while draining are turned away
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(MethodGet, "/slow", nil))
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Connection") != "close" {
+		t.Errorf("request while draining: status = %d, Connection = %q, want 503 and close", w.Code, w.Header().Get("Connection"))
+	}
+
+	select {
+	case err := <-shutdown:
+		t.Fatalf("Shutdown returned %v before the in-flight request finished", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-shutdown; err != nil {
+		t.Errorf("Shutdown() = %v, want nil", err)
+	}
+	if code := <-status; code != http.StatusNoContent {
+		t.Errorf("in-flight request status = %d, want %d", code, http.StatusNoContent)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Serve() = %v, want nil", err)
+	}
+}
+
+func TestShutdownCutOff(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	r := blockingRouter(started, release)
+	url, done := serveTest(t, r, ServerConfig{})
+
+	for i := 0; i < 2; i++ {
+		go func() {
+			if resp, err := http.Get(url + "/slow"); err == nil {
+				resp.Body.Close()
+			}
+		}()
+		<-started
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := r.Shutdown(ctx)
+	var shutdownErr *ShutdownError
+	if !errors.As(err, &shutdownErr) || shutdownErr.CutOff != 2 || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() = %v, want a *ShutdownError cutting off 2 requests", err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Serve() = %v, want nil", err)
+	}
+	if again := r.Shutdown(context.Background()); again != nil {
+		t.Errorf("Shutdown() after the server stopped = %v, want nil", again)
+	}
+}
+
+func TestShutdownH2C(t *testing.T) {
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+			return net.Dial(network, addr)
+		},
+	}}
+
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		cutOff  int64
+	}{
+		{name: "drained", timeout: 2 * time.Second},
+		{name: "cut off", timeout: 50 * time.Millisecond, cutOff: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var finished atomic.Bool
+			started := make(chan struct{})
+			r := NewRouter()
+			r.GET("/slow", func(c *nexctx.Context) {
+				close(started)
+				time.Sleep(300 * time.Millisecond)
+				finished.Store(true)
+			})
+
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			hookSawFinished := make(chan bool, 1)
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+			go func() {
+				done <- r.Start(ctx, ServerConfig{
+					Listener:        ln,
+					H2C:             true,
+					ShutdownTimeout: tt.timeout,
+					OnShutdown: []func(context.Context) error{func(context.Context) error {
+						hookSawFinished <- finished.Load()
+						return nil
+					}},
+				})
+			}()
+
+			go func() {
+				resp, err := client.Get("http://" + ln.Addr().String() + "/slow")
+				if err == nil {
+					if resp.ProtoMajor != 2 {
+						t.Errorf("request served over %s, want HTTP/2", resp.Proto)
+					}
+					resp.Body.Close()
+				}
+			}()
+			<-started
+			cancel()
+
+			err = <-done
+			if tt.cutOff == 0 {
+				if err != nil {
+					t.Errorf("Start() = %v, want nil", err)
+				}
+				if !<-hookSawFinished {
+					t.Error("shutdown hooks ran before the in-flight request finished")
+				}
+				return
+			}
+
+			var shutdownErr *ShutdownError
+			if !errors.As(err, &shutdownErr) || shutdownErr.CutOff != tt.cutOff {
+				t.Errorf("Start() = %v, want a *ShutdownError cutting off %d request(s)", err, tt.cutOff)
+			}
+			<-hookSawFinished
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// Transport serves the router over a protocol the built-in server does not
+// speak, such as HTTP/3 over QUIC. Set it on ServerConfig to replace the
+// built-in server; handlers are unaffected.
+type Transport interface {
+	// Serve serves requests with handler until the transport is shut down
+	// or closed, then returns http.ErrServerClosed.
+	Serve(handler http.Handler) error
+	// Shutdown stops accepting requests and waits for in-flight ones to
+	// finish until ctx expires.
+	Shutdown(ctx context.Context) error
+	// Close stops the transport immediately, cutting off in-flight requests.
+	Close() error
+}
+
+// HTTP2Config tunes HTTP/2. Zero values leave the defaults of
+// golang.org/x/net/http2 in place.
+type HTTP2Config struct {
+	MaxConcurrentStreams         uint32
+	MaxReadFrameSize             uint32
+	MaxUploadBufferPerConnection int32
+	MaxUploadBufferPerStream     int32
+	IdleTimeout                  time.Duration
+}
+
+// httpTransport is the built-in transport serving HTTP/1.1 and HTTP/2 with a
+// http.Server on a net.Listener.
+type httpTransport struct {
+	server   *http.Server
+	listener net.Listener
+	useTLS   bool
+	certFile string
+	keyFile  string
+	h2c      *http2.Server // set when serving HTTP/2 over cleartext
+}
+
+// newHTTPTransport creates the built-in transport described by cfg and
+// starts listening.
+func newHTTPTransport(cfg ServerConfig) (*httpTransport, error) {
+	t := &httpTransport{
+		useTLS:   cfg.TLSConfig != nil || cfg.CertFile != "" || cfg.KeyFile != "",
+		certFile: cfg.CertFile,
+		keyFile:  cfg.KeyFile,
+	}
+
+	var err error
+	if t.listener, err = listen(cfg, t.useTLS); err != nil {
+		return nil, err
+	}
+
+	t.server = &http.Server{
+		Addr:              t.listener.Addr().String(),
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		TLSConfig:         cfg.TLSConfig,
+	}
+
+	if cfg.HTTP2 == nil && !cfg.H2C {
+		return t, nil
+	}
+
+	h2s := &http2.Server{}
+	if cfg.HTTP2 != nil {
+		h2s.MaxConcurrentStreams = cfg.HTTP2.MaxConcurrentStreams
+		h2s.MaxReadFrameSize = cfg.HTTP2.MaxReadFrameSize
+		h2s.MaxUploadBufferPerConnection = cfg.HTTP2.MaxUploadBufferPerConnection
+		h2s.MaxUploadBufferPerStream = cfg.HTTP2.MaxUploadBufferPerStream
+		h2s.IdleTimeout = cfg.HTTP2.IdleTimeout
+	}
+	// also lets a graceful shutdown reach HTTP/2 connections
+	if err := http2.ConfigureServer(t.server, h2s); err != nil {
+		t.listener.Close()
+		return nil, fmt.Errorf("configuring HTTP/2: %w", err)
+	}
+	if cfg.H2C && !t.useTLS {
+		t.h2c = h2s
+	}
+	return t, nil
+}
+
+// Serve implements Transport.
+func (t *httpTransport) Serve(handler http.Handler) error {
+	if t.h2c != nil {
+		handler = h2c.NewHandler(handler, t.h2c)
+	}
+	t.server.Handler = handler
+
+	if t.useTLS {
+		return t.server.ServeTLS(t.listener, t.certFile, t.keyFile)
+	}
+	return t.server.Serve(t.listener)
+}
+
+// Shutdown implements Transport.
+func (t *httpTransport) Shutdown(ctx context.Context) error {
+	return t.server.Shutdown(ctx)
+}
+
+// Close implements Transport. It also closes the listener if Serve was never
+// called.
+func (t *httpTransport) Close() error {
+	err := t.server.Close()
+	t.listener.Close()
+	return err
+}
+
+// listen returns the listener the server described by cfg accepts
+// connections on.
+func listen(cfg ServerConfig, useTLS bool) (net.Listener, error) {
+	if cfg.Listener != nil {
+		return cfg.Listener, nil
+	}
+
+	network, addr := cfg.Network, cfg.Addr
+	if network == "" {
+		network = "tcp"
+	}
+	if addr == "" && network == "tcp" {
+		addr = ":http"
+		if useTLS {
+			addr = ":https"
+		}
+	}
+	return net.Listen(network, addr)
+}
@@ -1,136 +1,730 @@
 package router
 
 import (
-	"log"
-	"reflect"
-	"runtime"
+	"fmt"
+	"sort"
 	"strings"
 )
 
-// node is a node in the tree structure.
+// node is a node in the compressed radix tree.
+//
+// Static nodes hold a path fragment that may span several segments and share
+// their common prefix with their siblings. Param nodes hold a single param
+// pattern such as ":id", ":id(\d+)" or ":id<int>", which matches within one
+// segment, or a catch-all such as "*filepath" that matches the rest of the
+// path.
 type node struct {
 	path        string
-	children    []*node
-	handler     HandlerFunc
 	param       *paramMatcher
-	middlewares []MiddlewareFunc
-	method      string
+	indices     string              // first byte of each static child, in the same order as statics
+	statics     []*node             // static children
+	constrained []*node             // regex and typed param children, in registration order
+	paramChild  *node               // simple param child
+	wildcard    *node               // catch-all child
+	routes      map[string][]*Route // routes keyed by HTTP method, in registration order
 }
 
-// insert inserts a new node in the tree.
-func (n *node) insert(segments []string, method string, handler HandlerFunc, middlewares ...MiddlewareFunc) {
-	if len(segments) == 0 {
-		n.handler = handler
-		n.middlewares = middlewares
-		return
-	}
+// paramValue is a param captured while searching the tree.
+type paramValue struct {
+	name  string
+	value string
+	typed any // value converted by the param's type, if it has one
+}
+
+// routePart is a static fragment or a param segment of a route pattern.
+type routePart struct {
+	pattern string
+	param   *paramMatcher
+}
+
+// insert inserts the pattern parts below the node and returns the node that
+// terminates the pattern.
+func (n *node) insert(parts []routePart) (*node, error) {
+	current := n
+	for _, part := range parts {
+		if part.param == nil {
+			current = current.insertStatic(part.pattern)
+			continue
+		}
 
-	n.method = method
-	segment := segments[0]
-	child := n.matchChild(segment)
-	if child == nil {
-		child = &node{path: segment}
 		var err error
-		child.param, err = newParamMatcher(segment)
+		current, err = current.insertParam(part.pattern, part.param)
 		if err != nil {
-			log.Fatalf("Failed to create param matcher: %v", err)
-			return
+			return nil, err
+		}
+	}
+	return current, nil
+}
+
+// insertStatic inserts a static fragment below the node, splitting existing
+// children when they only share part of their prefix.
+func (n *node) insertStatic(path string) *node {
+	if path == "" {
+		return n
+	}
+
+	if i := strings.IndexByte(n.indices, path[0]); i != -1 {
+		child := n.statics[i]
+		l := commonPrefix(path, child.path)
+		if l < len(child.path) {
+			child.split(l)
 		}
-		n.children = append(n.children, child)
+		return child.insertStatic(path[l:])
+	}
+
+	child := &node{path: path}
+	n.indices += path[:1]
+	n.statics = append(n.statics, child)
+	return child
+}
+
+// insertParam returns the param child for the segment, creating it if needed.
+// Params at the same position must agree on their name, and two constrained
+// params with the same constraint but different names are ambiguous.
+func (n *node) insertParam(segment string, pm *paramMatcher) (*node, error) {
+	switch pm.ptype {
+	case regexParam, typedParam:
+		for _, child := range n.constrained {
+			if child.path == segment {
+				return child, nil
+			}
+			if child.param.constraint == pm.constraint {
+				return nil, fmt.Errorf("%w: %q matches the same values as %q", ErrAmbiguousRoute, segment, child.path)
+			}
+		}
+		child := &node{path: segment, param: pm}
+		n.constrained = append(n.constrained, child)
+		return child, nil
+	case wildcardParam:
+		if n.wildcard == nil {
+			n.wildcard = &node{path: segment, param: pm}
+		} else if n.wildcard.param.name != pm.name {
+			return nil, fmt.Errorf("%w: %q conflicts with %q", ErrParamConflict, segment, n.wildcard.path)
+		}
+		return n.wildcard, nil
+	default:
+		if n.paramChild == nil {
+			n.paramChild = &node{path: segment, param: pm}
+		} else if n.paramChild.param.name != pm.name {
+			return nil, fmt.Errorf("%w: %q conflicts with %q", ErrParamConflict, segment, n.paramChild.path)
+		}
+		return n.paramChild, nil
+	}
+}
+
+// split cuts the node's path at i. The node keeps the leading part and
+// everything it held moves to a new static child holding the rest.
+func (n *node) split(i int) {
+	tail := *n
+	tail.path = n.path[i:]
+	*n = node{
+		path:    n.path[:i],
+		indices: tail.path[:1],
+		statics: []*node{&tail},
+	}
+}
+
+// addRoute registers the route on the node for the method. Several routes
+// can share a method when request matchers tell them apart.
+func (n *node) addRoute(method string, rt *Route) {
+	if n.routes == nil {
+		n.routes = make(map[string][]*Route)
 	}
-	child.insert(segments[1:], method, handler, middlewares...)
+	n.routes[method] = append(n.routes[method], rt)
 }
 
-// matchChild matches a child node with the given segment.
-func (n *node) matchChild(segment string) *node {
-	for _, child := range n.children {
-		if child.path == segment {
-			return child
+// handles reports whether the node terminates a route for the method.
+func (n *node) handles(method string) bool {
+	return len(n.routes[method]) > 0
+}
+
+// methods returns the methods registered on the node, sorted.
+func (n *node) methods() []string {
+	methods := make([]string, 0, len(n.routes))
+	for method := range n.routes {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// search looks for the node terminating path below n that handles method.
+// Static children are tried first, then regex and typed params, simple
+// params and finally the catch-all. When a branch dead-ends the search
+// backtracks and tries the next candidate. A catch-all also matches an empty
+// rest, so "/assets/*filepath" serves "/assets/" and "/assets" with an empty
+// filepath.
+func (n *node) search(method, path string, params *[]paramValue) *node {
+	if path == "" {
+		if n.handles(method) {
+			return n
+		}
+		if n.wildcard != nil {
+			return n.wildcard.searchParam(method, path, 0, params)
 		}
-		if child.param != nil {
-			matched, _ := child.param.match(segment)
-			if matched {
-				return child
+		if i := strings.IndexByte(n.indices, '/'); i != -1 && n.statics[i].path == "/" && n.statics[i].wildcard != nil {
+			return n.statics[i].search(method, path, params)
+		}
+		return nil
+	}
+
+	if i := strings.IndexByte(n.indices, path[0]); i != -1 {
+		child := n.statics[i]
+		if strings.HasPrefix(path, child.path) {
+			if found := child.search(method, path[len(child.path):], params); found != nil {
+				return found
 			}
+		} else if child.wildcard != nil && len(child.path) == len(path)+1 && child.path[len(path)] == '/' && strings.HasPrefix(child.path, path) {
+			// the path stops right before the slash leading to a catch-all
+			if found := child.search(method, "", params); found != nil {
+				return found
+			}
+		}
+	}
+
+	if len(n.constrained) == 0 && n.paramChild == nil && n.wildcard == nil {
+		return nil
+	}
+
+	end := strings.IndexByte(path, '/')
+	if end == -1 {
+		end = len(path)
+	}
+	if end == 0 {
+		if n.wildcard != nil {
+			return n.wildcard.searchParam(method, path, len(path), params)
+		}
+		return nil
+	}
+
+	for _, child := range n.constrained {
+		if found := child.searchParam(method, path, end, params); found != nil {
+			return found
+		}
+	}
+	if n.paramChild != nil {
+		if found := n.paramChild.searchParam(method, path, end, params); found != nil {
+			return found
+		}
+	}
+	if n.wildcard != nil {
+		return n.wildcard.searchParam(method, path, len(path), params)
+	}
+	return nil
+}
+
+// searchParam matches a value at the start of path, at most end bytes long,
+// against the param node and continues the search with what follows. When
+// static text follows the param inside the segment, the value ends at the
+// first occurrence of that text; later occurrences are tried if the rest of
+// the path does not match. Captured values are dropped again on a dead end.
+func (n *node) searchParam(method, path string, end int, params *[]paramValue) *node {
+	i := end
+	if n.indices != "" && n.indices != "/" {
+		i = 1
+	}
+
+	mark := len(*params)
+	for ; i <= end; i++ {
+		if i < end && strings.IndexByte(n.indices, path[i]) == -1 {
+			continue
+		}
+
+		value := path[:i]
+		typed, matched := n.param.match(value)
+		if !matched {
+			continue
 		}
+
+		*params = append(*params, paramValue{name: n.param.name, value: value, typed: typed})
+		if found := n.search(method, path[i:], params); found != nil {
+			return found
+		}
+		*params = (*params)[:mark]
 	}
 	return nil
 }
 
-// search searches for a node in the tree.
-func (n *node) search(segments []string, params map[string]string) (HandlerFunc, []MiddlewareFunc) {
-	if len(segments) == 0 || (len(segments) == 1 && segments[0] == "") {
-		return n.handler, n.middlewares
+// searchFold is like search but matches static text case-insensitively. It
+// appends the matched path to fixed, with static text in the case it was
+// registered with and param values as they appear in path.
+func (n *node) searchFold(method, path string, fixed []byte) ([]byte, bool) {
+	if path == "" {
+		return fixed, n.handles(method)
 	}
 
-	segment := segments[0]
-	for _, child := range n.children {
-		if child.path == segment {
-			return child.search(segments[1:], params)
+	for _, child := range n.statics {
+		if len(path) >= len(child.path) && strings.EqualFold(path[:len(child.path)], child.path) {
+			if out, found := child.searchFold(method, path[len(child.path):], append(fixed, child.path...)); found {
+				return out, true
+			}
 		}
-		if child.param != nil {
-			matched, value := child.param.match(segment)
-			if matched {
-				params[child.param.name] = value
-				return child.search(segments[1:], params)
+	}
+
+	end := strings.IndexByte(path, '/')
+	if end == -1 {
+		end = len(path)
+	}
+	if end == 0 {
+		if n.wildcard != nil && n.wildcard.handles(method) {
+			return append(fixed, path...), true
+		}
+		return fixed, false
+	}
+
+	params := n.constrained
+	if n.paramChild != nil {
+		params = append(params[:len(params):len(params)], n.paramChild)
+	}
+	for _, child := range params {
+		for i := 1; i <= end; i++ {
+			if _, matched := child.param.match(path[:i]); !matched {
+				continue
+			}
+			if out, found := child.searchFold(method, path[i:], append(fixed, path[:i]...)); found {
+				return out, true
 			}
 		}
 	}
-	return nil, nil
+	if n.wildcard != nil && n.wildcard.handles(method) {
+		return append(fixed, path...), true
+	}
+	return fixed, false
+}
+
+// children returns the node's children in matching priority order.
+func (n *node) children() []*node {
+	children := make([]*node, 0, len(n.statics)+len(n.constrained)+2)
+	children = append(children, n.statics...)
+	children = append(children, n.constrained...)
+	if n.paramChild != nil {
+		children = append(children, n.paramChild)
+	}
+	if n.wildcard != nil {
+		children = append(children, n.wildcard)
+	}
+	return children
 }
 
 // Tree is a tree structure that holds the routes.
 type Tree struct {
-	root *node
+	root        *node
+	paramTypes  map[string]paramTypeFactory
+	strictSlash bool     // keep trailing slashes significant
+	methods     []string // every method a route is registered for, sorted
+	reserved    []string // param names taken by host placeholders
+	detached    bool     // tree of an invalid host, never served
 }
 
 // NewTree creates a new tree.
 func NewTree() *Tree {
-	return &Tree{root: &node{}}
+	paramTypes := make(map[string]paramTypeFactory, len(defaultParamTypes))
+	for name, factory := range defaultParamTypes {
+		paramTypes[name] = factory
+	}
+	return &Tree{root: &node{}, paramTypes: paramTypes}
 }
 
 // AddRoute adds a new route to the tree.
-func (t *Tree) AddRoute(method, path string, handler HandlerFunc, middlewares ...MiddlewareFunc) {
-	segments := splitPath(path)
-	t.root.insert(segments, method, handler, middlewares...) // Add method here
+func (t *Tree) AddRoute(method, path string, handler HandlerFunc, middlewares ...MiddlewareFunc) (*Route, error) {
+	rt := &Route{
+		methods:     []string{method},
+		path:        path,
+		handler:     handler,
+		middlewares: middlewares,
+	}
+	if err := t.add(rt); err != nil {
+		return nil, err
+	}
+	return rt, nil
 }
 
-// Match matches a path against the tree.
-func (t *Tree) Match(path string) (HandlerFunc, map[string]string, []MiddlewareFunc) {
-	segments := splitPath(path)
-	params := make(map[string]string)
-	handler, middlewares := t.root.search(segments, params)
-	return handler, params, middlewares
+// add inserts the route into the tree under each of its methods. Routes with
+// optional parts are inserted once per variant. It fails without registering
+// anything if the pattern is invalid or conflicts with the params of another
+// route. Duplicate routes are reported by duplicates, once their request
+// matchers are known.
+func (t *Tree) add(rt *Route) *RouteError {
+	patterns, err := expandOptional(rt.path)
+	if err != nil {
+		return &RouteError{Methods: rt.methods, Path: rt.path, Err: fmt.Errorf("%w: %v", ErrInvalidPattern, err)}
+	}
+
+	variants := make([][]routePart, 0, len(patterns))
+	for _, pattern := range patterns {
+		parts, err := parsePattern(t.normalize(pattern), t.paramTypes)
+		if err != nil {
+			return &RouteError{Methods: rt.methods, Path: rt.path, Err: fmt.Errorf("%w: %v", ErrInvalidPattern, err)}
+		}
+		for _, part := range parts {
+			if part.param != nil && contains(t.reserved, part.param.name) {
+				return &RouteError{Methods: rt.methods, Path: rt.path, Err: fmt.Errorf("%w: param %q is already a host placeholder", ErrParamConflict, part.param.name)}
+			}
+		}
+		variants = append(variants, parts)
+	}
+
+	leaves := make([]*node, 0, len(variants))
+	for _, parts := range variants {
+		leaf, err := t.root.insert(parts)
+		if err != nil {
+			return &RouteError{Methods: rt.methods, Path: rt.path, Err: err}
+		}
+		for _, other := range leaves {
+			if other == leaf {
+				return &RouteError{Methods: rt.methods, Path: rt.path, Err: fmt.Errorf("%w: optional parts match the same path", ErrAmbiguousRoute)}
+			}
+		}
+		leaves = append(leaves, leaf)
+	}
+
+	rt.variants = variants
+	for _, leaf := range leaves {
+		for _, method := range rt.methods {
+			leaf.addRoute(method, rt)
+		}
+	}
+	for _, method := range rt.methods {
+		if i := sort.SearchStrings(t.methods, method); i == len(t.methods) || t.methods[i] != method {
+			t.methods = append(t.methods, "")
+			copy(t.methods[i+1:], t.methods[i:])
+			t.methods[i] = method
+		}
+	}
+	return nil
+}
+
+// duplicates reports routes registered for the same method and path with the
+// same request matchers, which makes all but the first unreachable.
+func (t *Tree) duplicates() []*RouteError {
+	var errs []*RouteError
+	t.root.walk(func(n *node) {
+		for _, method := range n.methods() {
+			routes := n.routes[method]
+			for i, rt := range routes {
+				for _, earlier := range routes[:i] {
+					if earlier.matchersKey() == rt.matchersKey() {
+						errs = append(errs, &RouteError{
+							Methods: []string{method},
+							Path:    rt.path,
+							Err:     fmt.Errorf("%w: %s is already registered as %s", ErrDuplicateRoute, method, earlier.path),
+						})
+						break
+					}
+				}
+			}
+		}
+	})
+	return errs
+}
+
+// walk calls fn for the node and all its descendants.
+func (n *node) walk(fn func(*node)) {
+	fn(n)
+	for _, child := range n.children() {
+		child.walk(fn)
+	}
+}
+
+// Match matches a method and path against the tree. When several routes
+// share the method and path, the first one registered is returned.
+func (t *Tree) Match(method, path string) (HandlerFunc, map[string]string, []MiddlewareFunc) {
+	routes, values := t.lookup(method, path)
+
+	params := make(map[string]string, len(values))
+	if routes == nil {
+		return nil, params, nil
+	}
+	for _, p := range values {
+		params[p.name] = p.value
+	}
+	return routes[0].handler, params, routes[0].middlewares
+}
+
+// lookup returns the routes handling method and path together with the
+// params captured while matching them.
+func (t *Tree) lookup(method, path string) ([]*Route, []paramValue) {
+	var values []paramValue
+	routes := t.find(method, path, &values)
+	return routes, values
 }
 
-// splitPath splits a path into segments.
-func splitPath(path string) []string {
-	return strings.Split(strings.Trim(path, "/"), "/")
+// find returns the routes handling method and path and appends the params
+// captured while matching them to params. Nothing is appended when no route
+// matches.
+func (t *Tree) find(method, path string, params *[]paramValue) []*Route {
+	mark := len(*params)
+	n := t.root.search(method, t.normalize(path), params)
+	if n == nil {
+		*params = (*params)[:mark]
+		return nil
+	}
+	return n.routes[method]
+}
+
+// Allowed returns the methods the path is served for, sorted. Each method is
+// matched on its own, so the routes serving the path may differ per method.
+// It returns nil when no route matches the path under any method.
+func (t *Tree) Allowed(path string) []string {
+	path = t.normalize(path)
+
+	var allowed []string
+	var values []paramValue
+	for _, method := range t.methods {
+		if t.root.search(method, path, &values) != nil {
+			allowed = append(allowed, method)
+		}
+		values = values[:0]
+	}
+	return allowed
 }
 
-// printRoutes config
-type printRoutesConfig struct {
-	// PrintRoutes prints the routes in the tree.
-	PrintRoutes      bool
-	PrintMiddlewares bool
-	Prefix           string
+// expandOptional expands the optional parts of a route pattern into the
+// patterns it stands for, shortest first.
+//
+// An optional param is a whole segment followed by '?', as in "/posts/:page?".
+// Every segment after an optional param must be optional too. An optional
+// group is enclosed in brackets and must end the pattern; groups can be
+// nested, as in "/archive[/:year[/:month]]".
+func expandOptional(pattern string) ([]string, error) {
+	pattern, err := optionalParamsToGroups(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	open := -1
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case ':':
+			end, err := paramEnd(pattern, i)
+			if err != nil {
+				return nil, err
+			}
+			i = end - 1
+		case '[':
+			open = i
+		case ']':
+			return nil, fmt.Errorf("unexpected ']' at offset %d", i)
+		}
+		if open != -1 {
+			break
+		}
+	}
+	if open == -1 {
+		return []string{pattern}, nil
+	}
+
+	if !strings.HasSuffix(pattern, "]") {
+		return nil, fmt.Errorf("optional group at offset %d must end the route", open)
+	}
+	base, inner := pattern[:open], pattern[open+1:len(pattern)-1]
+	if inner == "" {
+		return nil, fmt.Errorf("empty optional group at offset %d", open)
+	}
+
+	expanded, err := expandOptional(base + inner)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{base}, expanded...), nil
 }
 
-// printRoutes prints the routes in the tree in a recursive manner.
-func (n *node) printRoutes(config printRoutesConfig) {
-	fullPath := config.Prefix + n.path
-	if n.handler != nil {
-		log.Printf("Route: %s", fullPath)
-		if config.PrintMiddlewares {
-			for _, middleware := range n.middlewares {
-				name := runtime.FuncForPC(reflect.ValueOf(middleware).Pointer()).Name()
-				log.Printf("\tMiddleware: %s", name)
+// optionalParamsToGroups rewrites optional params as nested optional groups,
+// turning "/posts/:page?/:sort?" into "/posts[/:page[/:sort]]".
+func optionalParamsToGroups(pattern string) (string, error) {
+	segments := strings.Split(pattern, "/")
+	first := -1
+	for i, segment := range segments {
+		optional := strings.HasPrefix(segment, ":") && strings.HasSuffix(segment, "?")
+		if optional {
+			if end, err := paramEnd(segment, 0); err != nil || end != len(segment)-1 {
+				optional = false
 			}
 		}
+
+		switch {
+		case optional && first == -1:
+			first = i
+		case !optional && first != -1:
+			return "", fmt.Errorf("segment %q follows an optional param and must be optional too", segment)
+		}
 	}
-	for _, child := range n.children {
-		child.printRoutes(printRoutesConfig{
-			Prefix: fullPath + "/",
-		})
+	if first == -1 {
+		return pattern, nil
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Join(segments[:first], "/"))
+	for _, segment := range segments[first:] {
+		b.WriteString("[/")
+		b.WriteString(strings.TrimSuffix(segment, "?"))
+	}
+	b.WriteString(strings.Repeat("]", len(segments)-first))
+	return b.String(), nil
+}
+
+// parsePattern splits a route pattern into its parts and builds the param
+// matchers of its param segments. A catch-all is only allowed as the last
+// segment.
+func parsePattern(pattern string, types map[string]paramTypeFactory) ([]routePart, error) {
+	segments, err := splitPattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]routePart, 0, len(segments))
+	for i, segment := range segments {
+		pm, err := newParamMatcher(segment, types)
+		if err != nil {
+			return nil, err
+		}
+		if pm != nil && pm.ptype == wildcardParam && i != len(segments)-1 {
+			return nil, fmt.Errorf("catch-all param %q must be the last segment", segment)
+		}
+		parts = append(parts, routePart{pattern: segment, param: pm})
+	}
+	return parts, nil
+}
+
+// splitPattern breaks a route pattern into static fragments and params.
+//
+// A param starts with ':' and its name runs over letters, digits and '_',
+// optionally followed by a "(regex)" or "<type>" constraint. Whatever follows
+// is static text again, so one segment can mix text and several params, as
+// in "/files/:name.:ext" or "/v:version/items". Two params must be separated
+// by static text. A catch-all starts with '*' at the beginning of a segment.
+// A backslash makes a colon literal, as in "/v1/users\\:batchGet".
+func splitPattern(pattern string) ([]string, error) {
+	var parts []string
+	var static strings.Builder
+	lastParam := false
+
+	flush := func() {
+		if static.Len() > 0 {
+			parts = append(parts, static.String())
+			static.Reset()
+		}
+	}
+
+	for i := 0; i < len(pattern); {
+		switch c := pattern[i]; {
+		case c == '\\' && i+1 < len(pattern) && pattern[i+1] == ':':
+			static.WriteByte(':')
+			lastParam = false
+			i += 2
+		case c == ':' || (c == '*' && (i == 0 || pattern[i-1] == '/')):
+			var end int
+			if c == '*' {
+				end = strings.IndexByte(pattern[i:], '/')
+				if end == -1 {
+					end = len(pattern)
+				} else {
+					end += i
+				}
+			} else {
+				var err error
+				if end, err = paramEnd(pattern, i); err != nil {
+					return nil, err
+				}
+			}
+			if lastParam && static.Len() == 0 {
+				return nil, fmt.Errorf("param %q must be separated from the previous param by static text", pattern[i:end])
+			}
+			flush()
+			parts = append(parts, pattern[i:end])
+			lastParam = true
+			i = end
+		default:
+			static.WriteByte(c)
+			lastParam = false
+			i++
+		}
+	}
+	flush()
+
+	return parts, nil
+}
+
+// paramEnd returns the index right after the param starting with ':' at i:
+// its name and, if present, its "(regex)" or "<type>" constraint.
+func paramEnd(pattern string, i int) (int, error) {
+	j := i + 1
+	for j < len(pattern) && isNameChar(pattern[j]) {
+		j++
+	}
+	if j == i+1 {
+		return 0, fmt.Errorf("param at offset %d has no name", i)
+	}
+	if j == len(pattern) {
+		return j, nil
+	}
+
+	switch pattern[j] {
+	case '(':
+		depth := 0
+		for k := j; k < len(pattern); k++ {
+			switch pattern[k] {
+			case '\\':
+				k++
+			case '(':
+				depth++
+			case ')':
+				depth--
+				if depth == 0 {
+					return k + 1, nil
+				}
+			}
+		}
+		return 0, fmt.Errorf("unterminated regex in param %q", pattern[i:j])
+	case '<':
+		k := strings.IndexByte(pattern[j:], '>')
+		if k == -1 {
+			return 0, fmt.Errorf("unterminated type in param %q", pattern[i:j])
+		}
+		return j + k + 1, nil
+	}
+	return j, nil
+}
+
+// isNameChar reports whether c can be part of a param name.
+func isNameChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// normalize prepares a pattern or request path for the tree. Unless trailing
+// slashes are strict they are removed, so "/a/" and "/a" are the same path.
+func (t *Tree) normalize(path string) string {
+	if t.strictSlash {
+		return path
+	}
+	return trimTrailingSlash(path)
+}
+
+// FindCaseInsensitive looks the path up ignoring the case of static text and
+// returns it rewritten with the case the route was registered with.
+func (t *Tree) FindCaseInsensitive(method, path string) (string, bool) {
+	fixed, found := t.root.searchFold(method, t.normalize(path), make([]byte, 0, len(path)+1))
+	if !found {
+		return "", false
+	}
+	if len(fixed) == 0 || fixed[0] != '/' {
+		fixed = append([]byte{'/'}, fixed...)
+	}
+	return string(fixed), true
+}
+
+// trimTrailingSlash removes trailing slashes so "/a/" and "/a" are the same path.
+func trimTrailingSlash(path string) string {
+	return strings.TrimRight(path, "/")
+}
+
+// commonPrefix returns the length of the common prefix of a and b.
+func commonPrefix(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
 	}
+	return i
 }
@@ -0,0 +1,403 @@
+package router
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// match describes the expected outcome of looking a path up in a tree.
+type match struct {
+	path   string            // request path
+	route  string            // pattern of the route expected to match, "" for none
+	params map[string]string // expected params, nil for none
+}
+
+// newTestTree registers each pattern under GET in a new tree.
+func newTestTree(t *testing.T, patterns ...string) *Tree {
+	t.Helper()
+
+	tree := NewTree()
+	for _, pattern := range patterns {
+		if _, err := tree.AddRoute(MethodGet, pattern, nil); err != nil {
+			t.Fatalf("AddRoute(%q): %v", pattern, err)
+		}
+	}
+	return tree
+}
+
+// checkMatches looks each path up in the tree and compares the route that
+// matched and the params captured with the expected ones.
+func checkMatches(t *testing.T, tree *Tree, matches []match) {
+	t.Helper()
+
+	for _, m := range matches {
+		routes, values := tree.lookup(MethodGet, m.path)
+
+		got := ""
+		if routes != nil {
+			got = routes[0].path
+		}
+		if got != m.route {
+			t.Errorf("lookup(%q) matched %q, want %q", m.path, got, m.route)
+			continue
+		}
+
+		var params map[string]string
+		for _, v := range values {
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params[v.name] = v.value
+		}
+		if !reflect.DeepEqual(params, m.params) {
+			t.Errorf("lookup(%q) params = %v, want %v", m.path, params, m.params)
+		}
+	}
+}
+
+func TestTreePriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		matches  []match
+	}{
+		{
+			name:     "static before param",
+			patterns: []string{"/users/:id", "/users/me"},
+			matches: []match{
+				{path: "/users/me", route: "/users/me"},
+				{path: "/users/42", route: "/users/:id", params: map[string]string{"id": "42"}},
+				{path: "/users/mee", route: "/users/:id", params: map[string]string{"id": "mee"}},
+			},
+		},
+		{
+			name:     "static before param registered first",
+			patterns: []string{"/users/me", "/users/:id"},
+			matches: []match{
+				{path: "/users/me", route: "/users/me"},
+				{path: "/users/42", route: "/users/:id", params: map[string]string{"id": "42"}},
+			},
+		},
+		{
+			name:     "regex before plain param",
+			patterns: []string{"/posts/:slug", "/posts/:id(\\d+)"},
+			matches: []match{
+				{path: "/posts/42", route: "/posts/:id(\\d+)", params: map[string]string{"id": "42"}},
+				{path: "/posts/hello", route: "/posts/:slug", params: map[string]string{"slug": "hello"}},
+			},
+		},
+		{
+			name:     "catch-all last",
+			patterns: []string{"/files/*path", "/files/:name", "/files/readme"},
+			matches: []match{
+				{path: "/files/readme", route: "/files/readme"},
+				{path: "/files/a.txt", route: "/files/:name", params: map[string]string{"name": "a.txt"}},
+				{path: "/files/a/b.txt", route: "/files/*path", params: map[string]string{"path": "a/b.txt"}},
+			},
+		},
+		{
+			name:     "shared static prefixes",
+			patterns: []string{"/search", "/support", "/se", "/s"},
+			matches: []match{
+				{path: "/search", route: "/search"},
+				{path: "/support", route: "/support"},
+				{path: "/se", route: "/se"},
+				{path: "/s", route: "/s"},
+				{path: "/sea", route: ""},
+			},
+		},
+		{
+			name:     "no match",
+			patterns: []string{"/users/:id"},
+			matches: []match{
+				{path: "/users", route: ""},
+				{path: "/users/1/posts", route: ""},
+				{path: "/posts", route: ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkMatches(t, newTestTree(t, tt.patterns...), tt.matches)
+		})
+	}
+}
+
+func TestTreeBacktracking(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		matches  []match
+	}{
+		{
+			name:     "dead-end static branch falls back to param",
+			patterns: []string{"/a/:x/b", "/a/c/d"},
+			matches: []match{
+				{path: "/a/c/d", route: "/a/c/d"},
+				{path: "/a/c/b", route: "/a/:x/b", params: map[string]string{"x": "c"}},
+				{path: "/a/z/b", route: "/a/:x/b", params: map[string]string{"x": "z"}},
+				{path: "/a/c/e", route: ""},
+			},
+		},
+		{
+			name:     "dead-end regex branch falls back to plain param",
+			patterns: []string{"/n/:id(\\d+)/edit", "/n/:name/view"},
+			matches: []match{
+				{path: "/n/1/edit", route: "/n/:id(\\d+)/edit", params: map[string]string{"id": "1"}},
+				{path: "/n/1/view", route: "/n/:name/view", params: map[string]string{"name": "1"}},
+			},
+		},
+		{
+			name:     "dead-end param branch falls back to catch-all",
+			patterns: []string{"/p/:x/y", "/p/*rest"},
+			matches: []match{
+				{path: "/p/a/y", route: "/p/:x/y", params: map[string]string{"x": "a"}},
+				{path: "/p/a/z", route: "/p/*rest", params: map[string]string{"rest": "a/z"}},
+			},
+		},
+		{
+			name:     "params dropped on backtrack",
+			patterns: []string{"/:a/:b/c", "/:a/d"},
+			matches: []match{
+				{path: "/1/2/c", route: "/:a/:b/c", params: map[string]string{"a": "1", "b": "2"}},
+				{path: "/1/d", route: "/:a/d", params: map[string]string{"a": "1"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkMatches(t, newTestTree(t, tt.patterns...), tt.matches)
+		})
+	}
+}
+
+func TestTreeCatchAll(t *testing.T) {
+	tree := newTestTree(t, "/static/*filepath", "/static/index.html", "/assets/*")
+	checkMatches(t, tree, []match{
+		{path: "/static/index.html", route: "/static/index.html"},
+		{path: "/static/css/site.css", route: "/static/*filepath", params: map[string]string{"filepath": "css/site.css"}},
+		{path: "/static/a", route: "/static/*filepath", params: map[string]string{"filepath": "a"}},
+		{path: "/static", route: "/static/*filepath", params: map[string]string{"filepath": ""}},
+		{path: "/static/", route: "/static/*filepath", params: map[string]string{"filepath": ""}},
+		{path: "/static//x", route: "/static/*filepath", params: map[string]string{"filepath": "/x"}},
+		{path: "/assets/img/logo.png", route: "/assets/*", params: map[string]string{"*": "img/logo.png"}},
+	})
+}
+
+func TestTreeCatchAllEmptyRest(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		strict   bool
+		matches  []match
+	}{
+		{
+			name:     "single route",
+			patterns: []string{"/assets/*filepath"},
+			matches: []match{
+				{path: "/assets/", route: "/assets/*filepath", params: map[string]string{"filepath": ""}},
+				{path: "/assets", route: "/assets/*filepath", params: map[string]string{"filepath": ""}},
+				{path: "/assets//x", route: "/assets/*filepath", params: map[string]string{"filepath": "/x"}},
+				{path: "/asset", route: ""},
+			},
+		},
+		{
+			name:     "split static prefix",
+			patterns: []string{"/assets/*filepath", "/assetsx"},
+			matches: []match{
+				{path: "/assets", route: "/assets/*filepath", params: map[string]string{"filepath": ""}},
+				{path: "/assets/", route: "/assets/*filepath", params: map[string]string{"filepath": ""}},
+				{path: "/assetsx", route: "/assetsx"},
+			},
+		},
+		{
+			name:     "strict slashes",
+			patterns: []string{"/assets/*filepath"},
+			strict:   true,
+			matches: []match{
+				{path: "/assets/", route: "/assets/*filepath", params: map[string]string{"filepath": ""}},
+				{path: "/assets//x", route: "/assets/*filepath", params: map[string]string{"filepath": "/x"}},
+			},
+		},
+		{
+			name:     "empty segment after param",
+			patterns: []string{"/u/:id/*rest"},
+			matches: []match{
+				{path: "/u/1//a", route: "/u/:id/*rest", params: map[string]string{"id": "1", "rest": "/a"}},
+				{path: "/u/1", route: "/u/:id/*rest", params: map[string]string{"id": "1", "rest": ""}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := NewTree()
+			tree.strictSlash = tt.strict
+			for _, pattern := range tt.patterns {
+				if _, err := tree.AddRoute(MethodGet, pattern, nil); err != nil {
+					t.Fatalf("AddRoute(%q): %v", pattern, err)
+				}
+			}
+			checkMatches(t, tree, tt.matches)
+		})
+	}
+}
+
+func TestTreeInvalidRoutes(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string // the last one is expected to fail
+		err      error
+	}{
+		{name: "catch-all not last", patterns: []string{"/files/*path/edit"}, err: ErrInvalidPattern},
+		{name: "catch-all not last after param", patterns: []string{"/:dir/*path/:name"}, err: ErrInvalidPattern},
+		{name: "catch-all names differ", patterns: []string{"/files/*path", "/files/*name"}, err: ErrParamConflict},
+		{name: "param names differ", patterns: []string{"/users/:id", "/users/:uid/posts"}, err: ErrParamConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := NewTree()
+			last := len(tt.patterns) - 1
+			for _, pattern := range tt.patterns[:last] {
+				if _, err := tree.AddRoute(MethodGet, pattern, nil); err != nil {
+					t.Fatalf("AddRoute(%q): %v", pattern, err)
+				}
+			}
+
+			_, err := tree.AddRoute(MethodGet, tt.patterns[last], nil)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("AddRoute(%q) error = %v, want %v", tt.patterns[last], err, tt.err)
+			}
+		})
+	}
+}
+
+func TestTreeMixedSegments(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		matches  []match
+	}{
+		{
+			name:     "name and extension",
+			patterns: []string{"/files/:name.:ext"},
+			matches: []match{
+				{path: "/files/report.pdf", route: "/files/:name.:ext", params: map[string]string{"name": "report", "ext": "pdf"}},
+				{path: "/files/archive.tar.gz", route: "/files/:name.:ext", params: map[string]string{"name": "archive", "ext": "tar.gz"}},
+				{path: "/files/readme", route: ""},
+				{path: "/files/.pdf", route: ""},
+			},
+		},
+		{
+			name:     "static prefix",
+			patterns: []string{"/v:version/items"},
+			matches: []match{
+				{path: "/v2/items", route: "/v:version/items", params: map[string]string{"version": "2"}},
+				{path: "/2/items", route: ""},
+			},
+		},
+		{
+			name:     "range",
+			patterns: []string{"/range/:from-:to"},
+			matches: []match{
+				{path: "/range/1-10", route: "/range/:from-:to", params: map[string]string{"from": "1", "to": "10"}},
+				{path: "/range/1", route: ""},
+			},
+		},
+		{
+			name:     "constrained params",
+			patterns: []string{"/range/:from(\\d+)-:to(\\d+)", "/range/:name"},
+			matches: []match{
+				{path: "/range/1-10", route: "/range/:from(\\d+)-:to(\\d+)", params: map[string]string{"from": "1", "to": "10"}},
+				{path: "/range/a-b", route: "/range/:name", params: map[string]string{"name": "a-b"}},
+			},
+		},
+		{
+			name:     "escaped colon",
+			patterns: []string{"/v1/users\\:batchGet", "/v1/users/:id"},
+			matches: []match{
+				{path: "/v1/users:batchGet", route: "/v1/users\\:batchGet"},
+				{path: "/v1/users/42", route: "/v1/users/:id", params: map[string]string{"id": "42"}},
+				{path: "/v1/users:other", route: ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkMatches(t, newTestTree(t, tt.patterns...), tt.matches)
+		})
+	}
+}
+
+func TestTreeInvalidMixedSegments(t *testing.T) {
+	for _, pattern := range []string{"/files/:name:ext", "/files/:.ext", "/files/:a(\\d+"} {
+		if _, err := NewTree().AddRoute(MethodGet, pattern, nil); !errors.Is(err, ErrInvalidPattern) {
+			t.Errorf("AddRoute(%q) error = %v, want %v", pattern, err, ErrInvalidPattern)
+		}
+	}
+}
+
+func TestTreeOptional(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		matches  []match
+	}{
+		{
+			name:     "optional param",
+			patterns: []string{"/posts/:page?"},
+			matches: []match{
+				{path: "/posts", route: "/posts/:page?"},
+				{path: "/posts/2", route: "/posts/:page?", params: map[string]string{"page": "2"}},
+				{path: "/posts/2/3", route: ""},
+			},
+		},
+		{
+			name:     "optional constrained param",
+			patterns: []string{"/posts/:page(\\d+)?"},
+			matches: []match{
+				{path: "/posts", route: "/posts/:page(\\d+)?"},
+				{path: "/posts/2", route: "/posts/:page(\\d+)?", params: map[string]string{"page": "2"}},
+				{path: "/posts/two", route: ""},
+			},
+		},
+		{
+			name:     "nested optional groups",
+			patterns: []string{"/archive[/:year[/:month]]"},
+			matches: []match{
+				{path: "/archive", route: "/archive[/:year[/:month]]"},
+				{path: "/archive/2024", route: "/archive[/:year[/:month]]", params: map[string]string{"year": "2024"}},
+				{path: "/archive/2024/1", route: "/archive[/:year[/:month]]", params: map[string]string{"year": "2024", "month": "1"}},
+				{path: "/archive/2024/1/2", route: ""},
+			},
+		},
+		{
+			name:     "static sibling wins",
+			patterns: []string{"/posts/:page?", "/posts/latest"},
+			matches: []match{
+				{path: "/posts/latest", route: "/posts/latest"},
+				{path: "/posts", route: "/posts/:page?"},
+				{path: "/posts/3", route: "/posts/:page?", params: map[string]string{"page": "3"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkMatches(t, newTestTree(t, tt.patterns...), tt.matches)
+		})
+	}
+}
+
+func TestTreeInvalidOptional(t *testing.T) {
+	for _, pattern := range []string{"/archive[/:year", "/archive/:year]", "/a/:b?/c", "/a[/b]/c"} {
+		if _, err := NewTree().AddRoute(MethodGet, pattern, nil); !errors.Is(err, ErrInvalidPattern) {
+			t.Errorf("AddRoute(%q) error = %v, want %v", pattern, err, ErrInvalidPattern)
+		}
+	}
+}